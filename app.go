@@ -10,6 +10,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 
@@ -53,9 +54,25 @@ type App struct {
 	ctx    context.Context
 	logger logr.Logger
 
-	// watchMu protects watchCancel
-	watchMu     sync.Mutex
-	watchCancel context.CancelFunc
+	// clients caches cluster clients and resource managers per context/namespace
+	clients *clientPool
+
+	// watches fans node updates out to frontend subscriptions
+	watches *watchHub
+
+	// importCancel stops the running import, nil when none is running
+	importMu     sync.Mutex
+	importCancel context.CancelCauseFunc
+
+	// history is the undo/redo log of the flow open in the editor, nil when none is open
+	historyMu sync.Mutex
+	history   *editHistory
+
+	// capabilities caches what the user may do per context and namespace
+	capabilities *capabilityCache
+
+	// health probes the active context in the background
+	health healthMonitor
 }
 
 // Preferences stores user preferences
@@ -81,8 +98,12 @@ func getPreferencesPath() (string, error) {
 
 // NewApp creates a new App application struct
 func NewApp(l logr.Logger) *App {
+	clients := newClientPool()
 	return &App{
-		logger: l,
+		logger:       l,
+		clients:      clients,
+		watches:      newWatchHub(clients),
+		capabilities: newCapabilityCache(),
 	}
 }
 
@@ -97,6 +118,15 @@ func (a *App) startup(ctx context.Context) {
 		a.logger.Error(err, "Failed to setup PATH")
 	}
 
+	// Evict idle cluster clients in the background
+	a.clients.onWatchStatus = a.emitWatchStatus
+	go a.clients.run()
+
+	a.watches.emit = func(event string, data interface{}) {
+		runtime.EventsEmit(a.ctx, event, data)
+	}
+	a.watches.onStatus = a.emitWatchStatus
+
 	// Enable direct deep link event emission for URLs arriving while app is running
 	deepLinkStartup(a.ctx)
 }
@@ -136,6 +166,9 @@ func setupPATH() error {
 }
 
 func (a *App) shutdown(ctx context.Context) {
+	a.StopHealthMonitor()
+	a.watches.close()
+	a.clients.close()
 }
 
 // ShowAbout displays the About dialog with company and version info.
@@ -189,14 +222,13 @@ func (a *App) SavePreferences(contextName, namespace string) error {
 	return os.WriteFile(path, data, 0644)
 }
 
-// SaveFile opens a save dialog and writes content to the selected file
+// SaveFile opens a save dialog and writes content to the selected file.
+// The dialog filters follow the extension of defaultFilename.
 func (a *App) SaveFile(defaultFilename, content string) (string, error) {
+	filters := saveFileFilters(defaultFilename)
 	filepath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
 		DefaultFilename: defaultFilename,
-		Filters: []runtime.FileFilter{
-			{DisplayName: "JSON Files", Pattern: "*.json"},
-			{DisplayName: "All Files", Pattern: "*"},
-		},
+		Filters:         filters,
 	})
 	if err != nil {
 		return "", err
@@ -213,11 +245,27 @@ func (a *App) SaveFile(defaultFilename, content string) (string, error) {
 	return filepath, nil
 }
 
+// saveFileFilters returns save dialog filters for the file type of filename, JSON by default
+func saveFileFilters(filename string) []runtime.FileFilter {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return []runtime.FileFilter{
+			{DisplayName: "YAML Files", Pattern: "*.yaml;*.yml"},
+			{DisplayName: "All Files", Pattern: "*"},
+		}
+	}
+	return []runtime.FileFilter{
+		{DisplayName: "JSON Files", Pattern: "*.json"},
+		{DisplayName: "All Files", Pattern: "*"},
+	}
+}
+
 // OpenFile opens a file dialog and returns the file content
 func (a *App) OpenFile() (string, error) {
 	filepath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
 		Filters: []runtime.FileFilter{
 			{DisplayName: "JSON Files", Pattern: "*.json"},
+			{DisplayName: "YAML Files", Pattern: "*.yaml;*.yml"},
 			{DisplayName: "All Files", Pattern: "*"},
 		},
 	})
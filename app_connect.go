@@ -2,7 +2,6 @@ package main
 
 import (
   "fmt"
-  "os"
   "time"
 
   corev1 "k8s.io/api/core/v1"
@@ -12,19 +11,17 @@ import (
   "k8s.io/client-go/tools/clientcmd"
 )
 
-// authTimestamp is used to force credential refresh by changing cache key
-var authTimestamp = time.Now().UnixNano()
-
 type KubeContext struct {
   Name    string `json:"name"`
   Cluster string `json:"cluster"`
   User    string `json:"user"`
   Current bool   `json:"current"`
+  Source  string `json:"source"` // kubeconfig file the context is loaded from
 }
 
 func (a *App) GetKubeContexts() ([]KubeContext, error) {
-  // Load kubeconfig respecting KUBECONFIG env var
-  loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+  // Load kubeconfig respecting KUBECONFIG env var, plus files added in the app
+  loadingRules := kubeconfigLoadingRules()
   config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
     loadingRules, &clientcmd.ConfigOverrides{},
   ).RawConfig()
@@ -32,6 +29,7 @@ func (a *App) GetKubeContexts() ([]KubeContext, error) {
     return nil, err
   }
 
+  sources := kubeconfigContextSources()
   var contexts []KubeContext
   for name, ctx := range config.Contexts {
     contexts = append(contexts, KubeContext{
@@ -39,6 +37,7 @@ func (a *App) GetKubeContexts() ([]KubeContext, error) {
       Cluster: ctx.Cluster,
       User:    ctx.AuthInfo,
       Current: name == config.CurrentContext,
+      Source:  sources[name],
     })
   }
 
@@ -46,36 +45,14 @@ func (a *App) GetKubeContexts() ([]KubeContext, error) {
 }
 
 func (a *App) ConnectToCluster(contextName string) (*kubernetes.Clientset, error) {
-  // 1. Specify the path to the kubeconfig file and the desired context
-  loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-
-  // 2. Get the Kubernetes configuration (rest.Config) for the selected context
-  kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-    loadingRules,
-    &clientcmd.ConfigOverrides{
-      CurrentContext: contextName,
-    },
-  )
-
-  // The rest.Config contains the necessary cluster address, auth info, etc.
-  config, err := kubeConfig.ClientConfig()
-  if err != nil {
-    return nil, err
-  }
-
-  // 3. Create the Clientset to interact with the cluster API
-  clientset, err := kubernetes.NewForConfig(config)
-  if err != nil {
-    return nil, err
-  }
-
-  // You can now use the clientset to make API calls, e.g., get a list of Pods
-  return clientset, nil
+  // Load the context through the client pool, so the calls that follow reuse its
+  // config, clientset and exec credentials instead of building their own
+  return a.clients.clientset(contextName)
 }
 
 func (a *App) CheckAuthorization(contextName string) error {
-  // 1. Determine the path to the Kubeconfig file.
-  config, err := loadContextConfig(contextName)
+  // 1. Get the pooled clientset (loads kubeconfig on first use).
+  clientset, err := a.clients.clientset(contextName)
 
   if err != nil {
     // If auth fails (e.g., token expired, invalid credentials), it often manifests here
@@ -83,17 +60,28 @@ func (a *App) CheckAuthorization(contextName string) error {
     return fmt.Errorf("failed to build client configuration for context '%s': %w", contextName, err)
   }
 
-  // 3. Create the Kubernetes Clientset.
-  clientset, err := kubernetes.NewForConfig(config)
-  if err != nil {
-    return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
-  }
-
-  // 4. Perform a low-privilege API call (e.g., list all namespaces).
+  // 2. Perform a low-privilege API call (e.g., list all namespaces).
   // If authorization fails (401/403) or the server is unreachable, the call will return an error.
   _, err = clientset.CoreV1().Namespaces().List(a.ctx, v1.ListOptions{})
 
+  if isCredentialError(err) {
+    // Expired exec/OIDC credentials: re-run the plugin of this context and try once more
+    refresh, refreshErr := a.refreshContextAuth(contextName)
+    if refreshErr != nil {
+      return fmt.Errorf("failed to refresh credentials for context '%s': %w", contextName, refreshErr)
+    }
+    if err := refresh.loginRequired(); err != nil {
+      return err
+    }
+    if clientset, err = a.clients.clientset(contextName); err != nil {
+      return fmt.Errorf("failed to build client configuration for context '%s': %w", contextName, err)
+    }
+    _, err = clientset.CoreV1().Namespaces().List(a.ctx, v1.ListOptions{})
+  }
+
   if err != nil {
+    // Drop cached clients so the next attempt starts from fresh credentials
+    a.clients.invalidateContext(contextName)
     // This captures the final authorization failure message (e.g., "Unauthorized")
     return fmt.Errorf("API access denied or failed for context '%s': %w", contextName, err)
   }
@@ -102,32 +90,29 @@ func (a *App) CheckAuthorization(contextName string) error {
   return nil
 }
 
-// RefreshAuth forces credential refresh by updating timestamp and clearing any state
+// RefreshAuth forces credential refresh of every context and clears any state.
+// Use RefreshContextAuth to refresh a single context.
 func (a *App) RefreshAuth() {
-  authTimestamp = time.Now().UnixNano()
+  resetAuthStamps()
+  a.clients.invalidate()
+  a.capabilities.invalidate()
 }
 
 // GetNamespaces fetches and returns a list of all namespace names for a given cluster context.
 func (a *App) GetNamespaces(contextName string) ([]string, error) {
 
-  config, err := loadContextConfig(contextName)
+  clientset, err := a.clients.clientset(contextName)
   if err != nil {
     return nil, fmt.Errorf("failed to build client configuration for context '%s': %w", contextName, err)
   }
 
-  // 3. Create the Kubernetes Clientset.
-  clientset, err := kubernetes.NewForConfig(config)
-  if err != nil {
-    return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
-  }
-
-  // 4. List all namespaces.
+  // 2. List all namespaces.
   namespaceList, err := clientset.CoreV1().Namespaces().List(a.ctx, v1.ListOptions{})
   if err != nil {
     return nil, fmt.Errorf("failed to list namespaces for context '%s': %w", contextName, err)
   }
 
-  // 5. Extract just the names into a string slice.
+  // 3. Extract just the names into a string slice.
   namespaces := make([]string, len(namespaceList.Items))
   for i, ns := range namespaceList.Items {
     namespaces[i] = ns.Name
@@ -145,16 +130,11 @@ type OtelCollectorStatus struct {
 
 // CheckOtelCollector checks if the otel-collector deployment exists and has healthy pods.
 func (a *App) CheckOtelCollector(contextName, namespace string) (*OtelCollectorStatus, error) {
-  config, err := loadContextConfig(contextName)
+  clientset, err := a.clients.clientset(contextName)
   if err != nil {
     return nil, fmt.Errorf("failed to build client configuration: %w", err)
   }
 
-  clientset, err := kubernetes.NewForConfig(config)
-  if err != nil {
-    return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
-  }
-
   deploy, err := clientset.AppsV1().Deployments(namespace).Get(a.ctx, "tinysystems-otel-collector", v1.GetOptions{})
   if err != nil {
     return &OtelCollectorStatus{Installed: false, Message: "Not installed"}, nil
@@ -182,16 +162,11 @@ func (a *App) CheckOtelCollector(contextName, namespace string) (*OtelCollectorS
 
 // CreateNamespace creates a new Kubernetes namespace in the given context.
 func (a *App) CreateNamespace(contextName, namespace string) error {
-  config, err := loadContextConfig(contextName)
+  clientset, err := a.clients.clientset(contextName)
   if err != nil {
     return fmt.Errorf("failed to build client configuration: %w", err)
   }
 
-  clientset, err := kubernetes.NewForConfig(config)
-  if err != nil {
-    return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
-  }
-
   ns := &corev1.Namespace{
     ObjectMeta: v1.ObjectMeta{
       Name: namespace,
@@ -207,11 +182,7 @@ func (a *App) CreateNamespace(contextName, namespace string) error {
 }
 
 func loadContextConfig(contextName string) (*rest.Config, error) {
-  // Set env var with current timestamp to bust exec credential cache
-  // This forces gke-gcloud-auth-plugin to get fresh credentials
-  os.Setenv("TINY_AUTH_TS", fmt.Sprintf("%d", authTimestamp))
-
-  loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+  loadingRules := kubeconfigLoadingRules()
   config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
     loadingRules,
     &clientcmd.ConfigOverrides{
@@ -222,6 +193,10 @@ func loadContextConfig(contextName string) (*rest.Config, error) {
     return nil, err
   }
 
+  // Bust the exec credential cache of this context after a refresh
+  // This forces gke-gcloud-auth-plugin to get fresh credentials
+  withAuthStamp(config, contextName)
+
   // Force shorter timeout to fail fast on auth issues
   config.Timeout = 10 * time.Second
 
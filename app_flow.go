@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,7 +14,6 @@ import (
 	"github.com/tiny-systems/module/api/v1alpha1"
 	"github.com/tiny-systems/module/pkg/schema"
 	"github.com/tiny-systems/module/pkg/utils"
-	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 )
@@ -64,19 +62,9 @@ type NodePosition struct {
 	Y float64 `json:"y"`
 }
 
-var (
-	flowWatchMu     sync.Mutex
-	flowWatchCancel context.CancelFunc
-
-	// flowNodesCache stores all nodes for the current flow (for edge validation)
-	flowNodesCacheMu sync.RWMutex
-	flowNodesCache   map[string]v1alpha1.TinyNode
-)
-
-
 // startStatsStreaming starts streaming stats from the otel-collector for edge animations.
-func (a *App) startStatsStreaming(ctx context.Context, contextName, namespace, projectName, flowResourceName string) {
-	config, err := loadContextConfig(contextName)
+func (a *App) startStatsStreaming(ctx context.Context, contextName, namespace, projectName, flowResourceName string, emit func(interface{})) {
+	config, err := a.clients.config(contextName)
 	if err != nil {
 		a.logger.Error(err, "failed to load context config for stats streaming")
 		return
@@ -108,7 +96,7 @@ func (a *App) startStatsStreaming(ctx context.Context, contextName, namespace, p
 			}
 
 			if len(statsBatch) > 0 {
-				wailsruntime.EventsEmit(a.ctx, "flowNodeUpdate", FlowNodeEvent{
+				emit(FlowNodeEvent{
 					Type:  "STATS",
 					Graph: statsBatch,
 				})
@@ -143,21 +131,11 @@ func (a *App) GetFlowForEditor(contextName, namespace, projectName, flowResource
 	}
 
 	// Get ALL project nodes - needed for validation (same as platform uses clusterNodes.Items())
-	allNodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+	allNodesMap, err := a.getProjectNodesMap(contextName, namespace, projectName)
 	if err != nil {
 		return nil, fmt.Errorf("get project nodes: %w", err)
 	}
 
-	allNodesMap := make(map[string]v1alpha1.TinyNode, len(allNodes))
-	for _, node := range allNodes {
-		allNodesMap[node.Name] = node
-	}
-
-	// Cache ALL nodes for validation during watch
-	flowNodesCacheMu.Lock()
-	flowNodesCache = allNodesMap
-	flowNodesCacheMu.Unlock()
-
 	// Build elements - pass flowResourceName to filter which nodes to display
 	elements, err := buildFlowElements(a.ctx, allNodesMap, flowResourceName)
 	if err != nil {
@@ -411,164 +389,111 @@ func getDetailedValidationError(err jsonschema.Detailed, in map[string]interface
 	}
 }
 
-// WatchFlowNodes starts watching nodes for a specific flow and emits events.
-func (a *App) WatchFlowNodes(contextName, namespace, projectName, flowResourceName string) error {
-	flowWatchMu.Lock()
-	defer flowWatchMu.Unlock()
-
-	if flowWatchCancel != nil {
-		flowWatchCancel()
+// WatchFlowNodes subscribes to node updates of a flow. Updates are emitted on the
+// returned subscription's event name; several flows can be watched at once.
+func (a *App) WatchFlowNodes(contextName, namespace, projectName, flowResourceName string) (*WatchSubscription, error) {
+	// Make sure the project is reachable before handing out a subscription
+	if _, err := a.getNodeCache(contextName, namespace, projectName); err != nil {
+		return nil, fmt.Errorf("start watch: %w", err)
 	}
 
-	mgr, err := a.getManager(contextName, namespace)
-	if err != nil {
-		return err
-	}
-
-	watchCtx, cancel := context.WithCancel(a.ctx)
-	flowWatchCancel = cancel
-
-	watcher, err := mgr.WatchNodes(watchCtx, projectName)
-	if err != nil {
-		cancel()
-		return fmt.Errorf("start watch: %w", err)
+	key := watchKey{
+		kind:      watchKindFlow,
+		context:   contextName,
+		namespace: namespace,
+		project:   projectName,
+		flow:      flowResourceName,
 	}
 
-	go a.startStatsStreaming(watchCtx, contextName, namespace, projectName, flowResourceName)
+	sub := a.watches.subscribe(key, "flowNodeUpdate", func(ctx context.Context, eventType watch.EventType, node *v1alpha1.TinyNode, nodes func() map[string]v1alpha1.TinyNode) []interface{} {
+		return flowNodeEvents(ctx, eventType, node, nodes, flowResourceName)
+	}, func(ctx context.Context, emit func(interface{})) {
+		a.startStatsStreaming(ctx, contextName, namespace, projectName, flowResourceName, emit)
 
-	heartbeatTicker := time.NewTicker(2 * time.Second)
-
-	go func() {
-		currentWatcher := watcher
-		defer func() {
-			heartbeatTicker.Stop()
-			if currentWatcher != nil {
-				currentWatcher.Stop()
-			}
-		}()
+		heartbeatTicker := time.NewTicker(2 * time.Second)
+		defer heartbeatTicker.Stop()
 
 		for {
 			select {
-			case <-watchCtx.Done():
+			case <-ctx.Done():
 				return
-
 			case <-heartbeatTicker.C:
-				wailsruntime.EventsEmit(a.ctx, "flowNodeUpdate", FlowNodeEvent{Type: "TICK"})
+				emit(FlowNodeEvent{Type: "TICK"})
+			}
+		}
+	})
 
-			case event, ok := <-currentWatcher.ResultChan():
-				if !ok {
-					currentWatcher.Stop()
-					select {
-					case <-watchCtx.Done():
-						return
-					case <-time.After(time.Second):
-					}
+	return &sub, nil
+}
 
-					newWatcher, err := mgr.WatchNodes(watchCtx, projectName)
-					if err != nil {
-						a.logger.Error(err, "reconnect watch failed")
-						return
-					}
-					currentWatcher = newWatcher
-					continue
-				}
+// flowNodeEvents builds the editor updates for a node event: the node itself plus
+// every edge from or to it, re-validated against all project nodes.
+func flowNodeEvents(ctx context.Context, eventType watch.EventType, node *v1alpha1.TinyNode, nodes func() map[string]v1alpha1.TinyNode, flowResourceName string) []interface{} {
+	// Check if node belongs to or is shared with this flow - same as platform
+	belongsToFlow := node.Labels[v1alpha1.FlowNameLabel] == flowResourceName
+	sharedWithFlow := containsFlow(node.Annotations[v1alpha1.SharedWithFlowsAnnotation], flowResourceName)
+	notThisFlow := !belongsToFlow
 
-				node, ok := event.Object.(*v1alpha1.TinyNode)
-				if !ok {
-					continue
-				}
+	// Skip nodes that don't belong to and aren't shared with this flow
+	if notThisFlow && !sharedWithFlow {
+		return nil
+	}
 
-				// Check if node belongs to or is shared with this flow - same as platform
-				belongsToFlow := node.Labels[v1alpha1.FlowNameLabel] == flowResourceName
-				sharedWithFlow := containsFlow(node.Annotations[v1alpha1.SharedWithFlowsAnnotation], flowResourceName)
-				notThisFlow := !belongsToFlow
+	// ALL nodes from other flows are blocked - same as platform line 116-118
+	blocked := notThisFlow
 
-				// Skip nodes that don't belong to and aren't shared with this flow
-				if notThisFlow && !sharedWithFlow {
-					continue
-				}
+	update := FlowNodeEvent{
+		Type: string(eventType),
+		ID:   node.Name,
+	}
 
-				// ALL nodes from other flows are blocked - same as platform line 116-118
-				blocked := notThisFlow
+	if eventType == watch.Deleted {
+		return []interface{}{update}
+	}
 
-				// Update nodes cache (ALL project nodes for validation)
-				flowNodesCacheMu.Lock()
-				if flowNodesCache == nil {
-					flowNodesCache = make(map[string]v1alpha1.TinyNode)
-				}
-				if event.Type == watch.Deleted {
-					delete(flowNodesCache, node.Name)
-				} else {
-					flowNodesCache[node.Name] = *node
-				}
-				nodesMapCopy := make(map[string]v1alpha1.TinyNode, len(flowNodesCache))
-				for k, v := range flowNodesCache {
-					nodesMapCopy[k] = v
-				}
-				flowNodesCacheMu.Unlock()
+	var events []interface{}
 
-				update := FlowNodeEvent{
-					Type: string(event.Type),
-					ID:   node.Name,
-				}
+	// ALL project nodes for validation
+	nodesMap := nodes()
 
-				if event.Type != watch.Deleted {
-					update.Graph = buildNodeElement(node, blocked)
-
-					// Re-emit edges FROM this node (source edges)
-					for i := range node.Spec.Edges {
-						edge := &node.Spec.Edges[i]
-						wailsruntime.EventsEmit(a.ctx, "flowNodeUpdate", FlowNodeEvent{
-							Type:  string(event.Type),
-							ID:    edge.ID,
-							Graph: buildEdgeElement(watchCtx, node, edge, nodesMapCopy, flowResourceName, sharedWithFlow, nil),
-						})
-					}
+	update.Graph = buildNodeElement(node, blocked)
 
-					// Re-emit edges TO this node (target edges) — edge config lives on
-					// the target node, so when target changes, edges need re-validation
-					for _, otherNode := range nodesMapCopy {
-						if otherNode.Name == node.Name {
-							continue
-						}
-						for i := range otherNode.Spec.Edges {
-							edge := &otherNode.Spec.Edges[i]
-							targetNode, _ := utils.ParseFullPortName(edge.To)
-							if targetNode == node.Name {
-								otherShared := containsFlow(otherNode.Annotations[v1alpha1.SharedWithFlowsAnnotation], flowResourceName)
-								wailsruntime.EventsEmit(a.ctx, "flowNodeUpdate", FlowNodeEvent{
-									Type:  string(event.Type),
-									ID:    edge.ID,
-									Graph: buildEdgeElement(watchCtx, &otherNode, edge, nodesMapCopy, flowResourceName, otherShared, nil),
-								})
-							}
-						}
-					}
-				}
+	// Re-emit edges FROM this node (source edges)
+	for i := range node.Spec.Edges {
+		edge := &node.Spec.Edges[i]
+		events = append(events, FlowNodeEvent{
+			Type:  string(eventType),
+			ID:    edge.ID,
+			Graph: buildEdgeElement(ctx, node, edge, nodesMap, flowResourceName, sharedWithFlow, nil),
+		})
+	}
 
-				wailsruntime.EventsEmit(a.ctx, "flowNodeUpdate", update)
+	// Re-emit edges TO this node (target edges) — edge config lives on
+	// the target node, so when target changes, edges need re-validation
+	for _, otherNode := range nodesMap {
+		if otherNode.Name == node.Name {
+			continue
+		}
+		for i := range otherNode.Spec.Edges {
+			edge := &otherNode.Spec.Edges[i]
+			targetNode, _ := utils.ParseFullPortName(edge.To)
+			if targetNode == node.Name {
+				otherShared := containsFlow(otherNode.Annotations[v1alpha1.SharedWithFlowsAnnotation], flowResourceName)
+				events = append(events, FlowNodeEvent{
+					Type:  string(eventType),
+					ID:    edge.ID,
+					Graph: buildEdgeElement(ctx, &otherNode, edge, nodesMap, flowResourceName, otherShared, nil),
+				})
 			}
 		}
-	}()
-
-	return nil
-}
-
-// StopWatchFlowNodes stops the flow node watcher.
-func (a *App) StopWatchFlowNodes() error {
-	flowWatchMu.Lock()
-	defer flowWatchMu.Unlock()
-
-	if flowWatchCancel != nil {
-		flowWatchCancel()
-		flowWatchCancel = nil
 	}
 
-	// Clear nodes cache
-	flowNodesCacheMu.Lock()
-	flowNodesCache = nil
-	flowNodesCacheMu.Unlock()
+	return append(events, update)
+}
 
+// StopWatchFlowNodes ends a flow node subscription.
+func (a *App) StopWatchFlowNodes(subscriptionID string) error {
+	a.watches.unsubscribe(subscriptionID)
 	return nil
 }
 
@@ -603,6 +528,10 @@ func (a *App) GetAvailableComponents(contextName, namespace string) ([]Component
 
 // AddNode adds a new component node to a flow.
 func (a *App) AddNode(contextName, namespace, projectName, flowResourceName, componentName, componentDescription, moduleName, moduleVersion string, posX, posY float64) (map[string]interface{}, error) {
+	if err := a.requireWritable(contextName, namespace, createNodes); err != nil {
+		return nil, err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return nil, err
@@ -645,11 +574,17 @@ func (a *App) AddNode(contextName, namespace, projectName, flowResourceName, com
 		return nil, fmt.Errorf("get created node: %w", err)
 	}
 
+	a.recordEdit(contextName, namespace, "Add "+nodeLabel, nodeChange{name: createdNode.Name, after: createdNode.DeepCopy()})
+
 	return buildNodeElement(createdNode, false), nil // New nodes are never blocked
 }
 
 // DeleteNode deletes a node from a flow.
 func (a *App) DeleteNode(contextName, namespace, nodeResourceName string) error {
+	if err := a.requireWritable(contextName, namespace, deleteNodes, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -662,6 +597,14 @@ func (a *App) DeleteNode(contextName, namespace, nodeResourceName string) error
 
 	projectName := node.Labels[v1alpha1.ProjectNameLabel]
 
+	// Snapshot the project so undo also restores the edges cleaned up below
+	before := map[string]*v1alpha1.TinyNode{node.Name: node.DeepCopy()}
+	if projectName != "" {
+		if nodes, err := mgr.GetProjectNodes(a.ctx, projectName); err == nil {
+			before = snapshotNodes(nodes)
+		}
+	}
+
 	if err := mgr.DeleteNode(a.ctx, node); err != nil {
 		return fmt.Errorf("delete node: %w", err)
 	}
@@ -676,11 +619,28 @@ func (a *App) DeleteNode(contextName, namespace, nodeResourceName string) error
 		}
 	}
 
+	after := map[string]*v1alpha1.TinyNode{}
+	if projectName != "" {
+		if nodes, err := mgr.GetProjectNodes(a.ctx, projectName); err == nil {
+			after = snapshotNodes(nodes)
+		} else {
+			// Without the cleaned up nodes only the deleted node can be restored
+			before = map[string]*v1alpha1.TinyNode{node.Name: before[node.Name]}
+		}
+	}
+	// The node may still be listed while it terminates
+	delete(after, nodeResourceName)
+	a.recordEdit(contextName, namespace, "Delete "+nodeTitle(node), diffNodeSnapshots(before, after)...)
+
 	return nil
 }
 
 // UpdateNodePosition updates a node's position in the flow.
 func (a *App) UpdateNodePosition(contextName, namespace, nodeResourceName string, posX, posY float64) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -690,6 +650,7 @@ func (a *App) UpdateNodePosition(contextName, namespace, nodeResourceName string
 	if err != nil {
 		return fmt.Errorf("get node: %w", err)
 	}
+	before := node.DeepCopy()
 
 	if node.Annotations == nil {
 		node.Annotations = make(map[string]string)
@@ -701,11 +662,17 @@ func (a *App) UpdateNodePosition(contextName, namespace, nodeResourceName string
 		return fmt.Errorf("update node position: %w", err)
 	}
 
+	a.recordEdit(contextName, namespace, "Move "+nodeTitle(node), nodeChange{name: node.Name, before: before, after: node.DeepCopy()})
+
 	return nil
 }
 
 // UpdateNodeLabel updates a node's display label.
 func (a *App) UpdateNodeLabel(contextName, namespace, nodeResourceName, label string) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -715,6 +682,7 @@ func (a *App) UpdateNodeLabel(contextName, namespace, nodeResourceName, label st
 	if err != nil {
 		return fmt.Errorf("get node: %w", err)
 	}
+	before := node.DeepCopy()
 
 	if node.Annotations == nil {
 		node.Annotations = make(map[string]string)
@@ -725,11 +693,17 @@ func (a *App) UpdateNodeLabel(contextName, namespace, nodeResourceName, label st
 		return fmt.Errorf("update node label: %w", err)
 	}
 
+	a.recordEdit(contextName, namespace, "Rename "+nodeTitle(before), nodeChange{name: node.Name, before: before, after: node.DeepCopy()})
+
 	return nil
 }
 
 // UpdateNodeComment updates a node's comment.
 func (a *App) UpdateNodeComment(contextName, namespace, nodeResourceName, comment string) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -739,6 +713,7 @@ func (a *App) UpdateNodeComment(contextName, namespace, nodeResourceName, commen
 	if err != nil {
 		return fmt.Errorf("get node: %w", err)
 	}
+	before := node.DeepCopy()
 
 	if node.Annotations == nil {
 		node.Annotations = make(map[string]string)
@@ -749,11 +724,17 @@ func (a *App) UpdateNodeComment(contextName, namespace, nodeResourceName, commen
 		return fmt.Errorf("update node comment: %w", err)
 	}
 
+	a.recordEdit(contextName, namespace, "Comment "+nodeTitle(node), nodeChange{name: node.Name, before: before, after: node.DeepCopy()})
+
 	return nil
 }
 
 // RotateNode rotates a node by incrementing its spin value.
 func (a *App) RotateNode(contextName, namespace, nodeResourceName string) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -763,6 +744,7 @@ func (a *App) RotateNode(contextName, namespace, nodeResourceName string) error
 	if err != nil {
 		return fmt.Errorf("get node: %w", err)
 	}
+	before := node.DeepCopy()
 
 	if node.Annotations == nil {
 		node.Annotations = make(map[string]string)
@@ -775,11 +757,17 @@ func (a *App) RotateNode(contextName, namespace, nodeResourceName string) error
 		return fmt.Errorf("rotate node: %w", err)
 	}
 
+	a.recordEdit(contextName, namespace, "Rotate "+nodeTitle(node), nodeChange{name: node.Name, before: before, after: node.DeepCopy()})
+
 	return nil
 }
 
 // ToggleNodeDashboard toggles the dashboard visibility for a node.
 func (a *App) ToggleNodeDashboard(contextName, namespace, nodeResourceName string, enabled bool) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -789,6 +777,7 @@ func (a *App) ToggleNodeDashboard(contextName, namespace, nodeResourceName strin
 	if err != nil {
 		return fmt.Errorf("get node: %w", err)
 	}
+	before := node.DeepCopy()
 
 	if node.Labels == nil {
 		node.Labels = make(map[string]string)
@@ -804,6 +793,8 @@ func (a *App) ToggleNodeDashboard(contextName, namespace, nodeResourceName strin
 		return fmt.Errorf("update node dashboard setting: %w", err)
 	}
 
+	a.recordEdit(contextName, namespace, "Toggle dashboard of "+nodeTitle(node), nodeChange{name: node.Name, before: before, after: node.DeepCopy()})
+
 	return nil
 }
 
@@ -817,6 +808,10 @@ type NodeSettings struct {
 
 // UpdateNodeSettings updates a node's shared flows, dashboard, module, and component settings.
 func (a *App) UpdateNodeSettings(contextName, namespace, nodeResourceName string, settings NodeSettings) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -826,6 +821,7 @@ func (a *App) UpdateNodeSettings(contextName, namespace, nodeResourceName string
 	if err != nil {
 		return fmt.Errorf("get node: %w", err)
 	}
+	before := node.DeepCopy()
 
 	if node.Annotations == nil {
 		node.Annotations = make(map[string]string)
@@ -860,11 +856,17 @@ func (a *App) UpdateNodeSettings(contextName, namespace, nodeResourceName string
 		return fmt.Errorf("update node settings: %w", err)
 	}
 
+	a.recordEdit(contextName, namespace, "Change settings of "+nodeTitle(node), nodeChange{name: node.Name, before: before, after: node.DeepCopy()})
+
 	return nil
 }
 
 // UpdateNodeConfiguration updates a node's port configuration.
 func (a *App) UpdateNodeConfiguration(contextName, namespace, nodeResourceName, port, configuration, schema string) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -874,6 +876,7 @@ func (a *App) UpdateNodeConfiguration(contextName, namespace, nodeResourceName,
 	if err != nil {
 		return fmt.Errorf("get node: %w", err)
 	}
+	before := node.DeepCopy()
 
 	found := false
 	for i, portConfig := range node.Spec.Ports {
@@ -902,11 +905,17 @@ func (a *App) UpdateNodeConfiguration(contextName, namespace, nodeResourceName,
 		return fmt.Errorf("update node configuration: %w", err)
 	}
 
+	a.recordEdit(contextName, namespace, "Configure "+nodeTitle(node), nodeChange{name: node.Name, before: before, after: node.DeepCopy()})
+
 	return nil
 }
 
 // ConnectNodes creates an edge between two nodes.
 func (a *App) ConnectNodes(contextName, namespace, flowResourceName, sourceNode, sourcePort, targetNode, targetPort, configuration string) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -917,6 +926,7 @@ func (a *App) ConnectNodes(contextName, namespace, flowResourceName, sourceNode,
 	if err != nil {
 		return fmt.Errorf("get source node: %w", err)
 	}
+	srcBefore := srcNode.DeepCopy()
 
 	newEdge := v1alpha1.TinyNodeEdge{
 		ID:     uuid.New().String(),
@@ -929,6 +939,8 @@ func (a *App) ConnectNodes(contextName, namespace, flowResourceName, sourceNode,
 	if err := mgr.UpdateNodeSync(a.ctx, srcNode, 30*time.Second); err != nil {
 		return fmt.Errorf("connect nodes: %w", err)
 	}
+	changes := []nodeChange{{name: srcNode.Name, before: srcBefore, after: srcNode.DeepCopy()}}
+	description := "Connect " + nodeTitle(srcNode)
 
 	// Edge configuration lives on the TARGET node — the runner reads
 	// c.node.Spec.Ports where c.node is the message receiver
@@ -937,6 +949,7 @@ func (a *App) ConnectNodes(contextName, namespace, flowResourceName, sourceNode,
 		if err != nil {
 			return fmt.Errorf("get target node: %w", err)
 		}
+		tgtBefore := tgtNode.DeepCopy()
 
 		portConfig := v1alpha1.TinyNodePortConfig{
 			Port:          targetPort,
@@ -947,15 +960,23 @@ func (a *App) ConnectNodes(contextName, namespace, flowResourceName, sourceNode,
 		tgtNode.Spec.Ports = append(tgtNode.Spec.Ports, portConfig)
 
 		if err := mgr.UpdateNodeSync(a.ctx, tgtNode, 30*time.Second); err != nil {
+			a.recordEdit(contextName, namespace, description, changes...)
 			return fmt.Errorf("connect nodes (target config): %w", err)
 		}
+		changes = append(changes, nodeChange{name: tgtNode.Name, before: tgtBefore, after: tgtNode.DeepCopy()})
 	}
 
+	a.recordEdit(contextName, namespace, description, changes...)
+
 	return nil
 }
 
 // DisconnectNodes removes an edge between two nodes.
 func (a *App) DisconnectNodes(contextName, namespace, sourceNode, edgeID string) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -966,6 +987,7 @@ func (a *App) DisconnectNodes(contextName, namespace, sourceNode, edgeID string)
 	if err != nil {
 		return fmt.Errorf("get source node: %w", err)
 	}
+	srcBefore := srcNode.DeepCopy()
 
 	var targetTo string
 	var sourcePort string
@@ -983,6 +1005,7 @@ func (a *App) DisconnectNodes(contextName, namespace, sourceNode, edgeID string)
 	if err := mgr.UpdateNodeSync(a.ctx, srcNode, 30*time.Second); err != nil {
 		return fmt.Errorf("disconnect nodes: %w", err)
 	}
+	changes := []nodeChange{{name: srcNode.Name, before: srcBefore, after: srcNode.DeepCopy()}}
 
 	// Remove port config from TARGET node
 	if targetTo != "" {
@@ -994,6 +1017,7 @@ func (a *App) DisconnectNodes(contextName, namespace, sourceNode, edgeID string)
 
 			tgtNode, err := mgr.GetNode(a.ctx, targetNodeName, namespace)
 			if err == nil {
+				tgtBefore := tgtNode.DeepCopy()
 				newPorts := make([]v1alpha1.TinyNodePortConfig, 0, len(tgtNode.Spec.Ports))
 				for _, portConfig := range tgtNode.Spec.Ports {
 					if portConfig.Port == targetPort && portConfig.From == fromStr {
@@ -1003,17 +1027,25 @@ func (a *App) DisconnectNodes(contextName, namespace, sourceNode, edgeID string)
 				}
 				if len(newPorts) != len(tgtNode.Spec.Ports) {
 					tgtNode.Spec.Ports = newPorts
-					_ = mgr.UpdateNodeSync(a.ctx, tgtNode, 30*time.Second)
+					if mgr.UpdateNodeSync(a.ctx, tgtNode, 30*time.Second) == nil {
+						changes = append(changes, nodeChange{name: tgtNode.Name, before: tgtBefore, after: tgtNode.DeepCopy()})
+					}
 				}
 			}
 		}
 	}
 
+	a.recordEdit(contextName, namespace, "Disconnect "+nodeTitle(srcNode), changes...)
+
 	return nil
 }
 
 // UpdateEdgeConfiguration updates an edge's configuration.
 func (a *App) UpdateEdgeConfiguration(contextName, namespace, sourceNode, sourcePort, targetTo, configuration, flowID string) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -1033,6 +1065,7 @@ func (a *App) UpdateEdgeConfiguration(contextName, namespace, sourceNode, source
 	if err != nil {
 		return fmt.Errorf("get target node: %w", err)
 	}
+	before := node.DeepCopy()
 
 	fromStr := sourceNode + ":" + sourcePort
 
@@ -1058,11 +1091,17 @@ func (a *App) UpdateEdgeConfiguration(contextName, namespace, sourceNode, source
 		return fmt.Errorf("update edge configuration: %w", err)
 	}
 
+	a.recordEdit(contextName, namespace, "Configure edge to "+nodeTitle(node), nodeChange{name: node.Name, before: before, after: node.DeepCopy()})
+
 	return nil
 }
 
 // SaveFlowMeta saves flow viewport metadata.
 func (a *App) SaveFlowMeta(contextName, namespace, flowResourceName string, viewportX, viewportY, zoom float64) error {
+	if err := a.requireWritable(contextName, namespace, updateFlows); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -1089,16 +1128,22 @@ func (a *App) SaveFlowMeta(contextName, namespace, flowResourceName string, view
 
 // BatchUpdateNodePositions updates multiple node positions at once.
 func (a *App) BatchUpdateNodePositions(contextName, namespace string, positions map[string]NodePosition) error {
+	if err := a.requireWritable(contextName, namespace, updateNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
 	}
 
+	var changes []nodeChange
 	for nodeResourceName, pos := range positions {
 		node, err := mgr.GetNode(a.ctx, nodeResourceName, namespace)
 		if err != nil {
 			continue
 		}
+		before := node.DeepCopy()
 
 		if node.Annotations == nil {
 			node.Annotations = make(map[string]string)
@@ -1108,40 +1153,34 @@ func (a *App) BatchUpdateNodePositions(contextName, namespace string, positions
 
 		if err := mgr.UpdateNode(a.ctx, node); err != nil {
 			a.logger.Error(err, "update node position failed", "node", nodeResourceName)
+			continue
 		}
+		changes = append(changes, nodeChange{name: node.Name, before: before, after: node.DeepCopy()})
 	}
 
+	description := "Move nodes"
+	if len(changes) == 1 {
+		description = "Move " + nodeTitle(changes[0].after)
+	}
+	a.recordEdit(contextName, namespace, description, changes...)
+
 	return nil
 }
 
 // InspectNodePort returns the simulated data for a specific port.
 // If traceID is provided, it uses real runtime data from the trace instead of simulated data.
 func (a *App) InspectNodePort(contextName, namespace, projectName, nodeResourceName, portName, traceID string) (map[string]interface{}, error) {
-	mgr, err := a.getManager(contextName, namespace)
-	if err != nil {
-		return nil, err
-	}
-
-	nodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+	nodesMap, err := a.getProjectNodesMap(contextName, namespace, projectName)
 	if err != nil {
 		return nil, fmt.Errorf("get project nodes: %w", err)
 	}
 
-	nodesMap := make(map[string]v1alpha1.TinyNode, len(nodes))
-	var targetNode *v1alpha1.TinyNode
-	for _, n := range nodes {
-		nodesMap[n.Name] = n
-		if n.Name == nodeResourceName {
-			nodeCopy := n
-			targetNode = &nodeCopy
-		}
-	}
-
-	if targetNode == nil {
+	targetNode, ok := nodesMap[nodeResourceName]
+	if !ok {
 		return nil, fmt.Errorf("node not found: %s", nodeResourceName)
 	}
 
-	handles := utils.GetAllPortHandles(*targetNode)
+	handles := utils.GetAllPortHandles(targetNode)
 
 	var targetHandle map[string]interface{}
 	for _, handle := range handles {
@@ -1183,7 +1222,7 @@ func (a *App) InspectNodePort(contextName, namespace, projectName, nodeResourceN
 	// Load runtime data from trace if traceID is provided
 	var runtimeData map[string][]byte
 	if traceID != "" {
-		config, err := loadContextConfig(contextName)
+		config, err := a.clients.config(contextName)
 		if err == nil {
 			pfClient := NewPortForwardClient(config, namespace)
 			defer pfClient.Close()
@@ -1234,6 +1273,10 @@ func extractBytes(v interface{}) []byte {
 
 // RunNodeAction triggers an action on a node port.
 func (a *App) RunNodeAction(contextName, namespace, nodeResourceName, port, data string) error {
+	if err := a.requireWritable(contextName, namespace, createSignals); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -1354,7 +1397,7 @@ type TracesResponse struct {
 
 // GetTraces fetches traces for a specific flow
 func (a *App) GetTraces(contextName, namespace, projectName, flowName string, start, end, offset int64) (*TracesResponse, error) {
-	config, err := loadContextConfig(contextName)
+	config, err := a.clients.config(contextName)
 	if err != nil {
 		return nil, err
 	}
@@ -1396,7 +1439,7 @@ type TraceDataResponse struct {
 
 // GetTraceByID fetches a trace by its ID
 func (a *App) GetTraceByID(contextName, namespace, projectName, traceID string) (*TraceDataResponse, error) {
-	config, err := loadContextConfig(contextName)
+	config, err := a.clients.config(contextName)
 	if err != nil {
 		return nil, err
 	}
@@ -1428,27 +1471,17 @@ type ApplyTraceToFlowResponse struct {
 
 // ApplyTraceToFlow fetches trace stats and applies them to graph elements
 func (a *App) ApplyTraceToFlow(contextName, namespace, projectName, flowResourceName, traceID string) (*ApplyTraceToFlowResponse, error) {
-	mgr, err := a.getManager(contextName, namespace)
-	if err != nil {
-		return nil, err
-	}
-
 	// Get ALL project nodes (needed for validation, same as GetFlowForEditor)
-	allNodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+	allNodesMap, err := a.getProjectNodesMap(contextName, namespace, projectName)
 	if err != nil {
 		return nil, fmt.Errorf("get project nodes: %w", err)
 	}
 
-	allNodesMap := make(map[string]v1alpha1.TinyNode, len(allNodes))
-	for _, node := range allNodes {
-		allNodesMap[node.Name] = node
-	}
-
 	// Fetch trace data and extract statistics + runtime data
 	var traceStats *utils.TraceStatistics
 	var runtimeData map[string][]byte
 	if traceID != "" {
-		config, err := loadContextConfig(contextName)
+		config, err := a.clients.config(contextName)
 		if err != nil {
 			return nil, err
 		}
@@ -1530,6 +1563,10 @@ type TransferNodesRequest struct {
 // TransferNodes transfers nodes from one flow to another.
 // Connected nodes are automatically shared with the destination flow.
 func (a *App) TransferNodes(contextName, namespace string, req TransferNodesRequest) error {
+	if err := a.requireWritable(contextName, namespace, createNodes, updateNodes, deleteNodes); err != nil {
+		return err
+	}
+
 	mgr, err := a.getManager(contextName, namespace)
 	if err != nil {
 		return err
@@ -1552,10 +1589,11 @@ func (a *App) TransferNodes(contextName, namespace string, req TransferNodesRequ
 	}
 
 	// Get all nodes in the project
-	allNodes, err := mgr.GetProjectNodes(a.ctx, req.ProjectResourceName)
+	allNodes, err := a.getProjectNodes(contextName, namespace, req.ProjectResourceName)
 	if err != nil {
 		return fmt.Errorf("failed to list project nodes: %w", err)
 	}
+	before := snapshotNodes(allNodes)
 
 	// Build a map of all nodes for quick lookup
 	allNodesMap := make(map[string]*v1alpha1.TinyNode)
@@ -1732,6 +1770,15 @@ func (a *App) TransferNodes(contextName, namespace string, req TransferNodesRequ
 		}
 	}
 
+	if nodes, err := mgr.GetProjectNodes(a.ctx, req.ProjectResourceName); err == nil {
+		after := snapshotNodes(nodes)
+		// Moved nodes may still be listed while they terminate
+		for _, node := range nodesToDelete {
+			delete(after, node.Name)
+		}
+		a.recordEdit(contextName, namespace, fmt.Sprintf("Transfer %d nodes", len(req.NodeIDs)), diffNodeSnapshots(before, after)...)
+	}
+
 	return nil
 }
 
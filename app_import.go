@@ -0,0 +1,1359 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/resource"
+	"github.com/tiny-systems/module/pkg/utils"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// importTimeout bounds a whole project import
+	importTimeout = 5 * time.Minute
+	// importRollbackTimeout bounds reverting a failed import
+	importRollbackTimeout = 2 * time.Minute
+
+	// importPollInterval is how often readiness conditions are re-checked
+	importPollInterval = 500 * time.Millisecond
+	// importNodeReadyTimeout bounds waiting for the controller to pick up created nodes
+	importNodeReadyTimeout = 2 * time.Minute
+	// importVerifyTimeout bounds waiting for edge configurations to be persisted
+	importVerifyTimeout = 30 * time.Second
+	// importPageReadyTimeout bounds waiting for a created page to become queryable
+	importPageReadyTimeout = 30 * time.Second
+
+	// importWorkers bounds concurrent node creates and updates
+	importWorkers = 8
+	// importQPS and importBurst limit API requests made by import workers
+	importQPS   = 20
+	importBurst = 40
+)
+
+// errImportCancelled is the cancellation cause of an import stopped with CancelImport.
+var errImportCancelled = errors.New("import cancelled")
+
+// projectImporter applies a project export to a cluster. Every change goes through
+// the journal so a failed import can be rolled back.
+type projectImporter struct {
+	app         *App
+	mgr         *resource.Manager
+	namespace   string
+	projectName string
+	data        *utils.ProjectExport
+	journal     *importJournal
+	progress    func(string)
+	limiter     flowcontrol.RateLimiter
+
+	// mu guards the fields written by concurrent workers
+	mu       sync.Mutex
+	warnings []string
+
+	existingFlowNames   map[string]bool
+	existingNodesMap    map[string]v1alpha1.TinyNode
+	flowResourceNameMap map[string]string // old flow name -> new flow name
+	dependencyFlows     map[string]bool   // old names of flows only some nodes are imported from
+	edgeOnlyNodes       map[string]bool   // old IDs of nodes pulled in from dependency flows; existing ones only get edges
+	nodeIDMap           map[string]string // old node ID -> new node name
+	createdNodes        []string          // names of nodes created by this import
+	freshNodeNames      bool              // new names don't reuse the old node ID suffix, so copies can sit next to the originals
+	pruneFlowEdges      bool              // edges and edge configurations of imported flows that aren't in the import are removed
+
+	importedNodes       int
+	edgesBySourceNode   map[string][]v1alpha1.TinyNodeEdge
+	portConfigsByTarget map[string][]v1alpha1.TinyNodePortConfig
+	importedPages       int
+}
+
+// ImportProject imports a project export, as JSON or YAML, or a multi-document YAML bundle
+// of resource manifests into an existing project. If any step fails the
+// changes made so far are rolled back and the error lists what was reverted.
+func (a *App) ImportProject(contextName string, namespace string, projectName string, jsonData string) (*ImportResult, error) {
+	return a.importProject(contextName, namespace, projectName, jsonData, ImportOptions{})
+}
+
+// importProject imports all of the JSON data, or only the selection if one is given.
+func (a *App) importProject(contextName string, namespace string, projectName string, jsonData string, options ImportOptions) (*ImportResult, error) {
+	if err := a.requireWritable(contextName, namespace, createNodes, updateNodes, createFlows, updateFlows, createPages, updatePages); err != nil {
+		return nil, err
+	}
+
+	// Create a dedicated context with longer timeout for import operations
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), importTimeout)
+	defer timeoutCancel()
+
+	ctx, cancel := context.WithCancelCause(timeoutCtx)
+	defer cancel(nil)
+
+	if err := a.beginImport(cancel); err != nil {
+		return nil, err
+	}
+	defer a.endImport()
+
+	emitProgress := func(msg string) {
+		wailsruntime.EventsEmit(a.ctx, "import:progress", msg)
+	}
+
+	emitProgress("Validating import data...")
+
+	importData, err := a.parseProjectImport(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	_, result, err := a.applyImport(ctx, contextName, namespace, projectName, importData, options, emitProgress)
+	return result, err
+}
+
+// applyImport imports parsed data into a project, rolling back on failure. It returns the
+// importer so callers can inspect how flows and nodes were mapped.
+func (a *App) applyImport(ctx context.Context, contextName string, namespace string, projectName string, importData *utils.ProjectExport, options ImportOptions, emitProgress func(string)) (*projectImporter, *ImportResult, error) {
+	emitProgress("Connecting to cluster...")
+
+	mgr, err := a.getManager(contextName, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	imp := &projectImporter{
+		app:         a,
+		mgr:         mgr,
+		namespace:   namespace,
+		projectName: projectName,
+		data:        importData,
+		journal:     &importJournal{},
+		progress:    emitProgress,
+		limiter:     flowcontrol.NewTokenBucketRateLimiter(importQPS, importBurst),
+
+		pruneFlowEdges: options.pruneFlowEdges,
+	}
+	if options.Selection != nil {
+		if err := imp.selectOnly(*options.Selection); err != nil {
+			return nil, nil, err
+		}
+		for _, w := range imp.warnings {
+			a.logger.Info("import selection: " + w)
+		}
+	}
+	if options.SubstituteVariables {
+		if missing := imp.substituteVariables(options.Variables); len(missing) > 0 {
+			return nil, nil, fmt.Errorf("missing values for import variables: %s", strings.Join(missing, ", "))
+		}
+	}
+
+	runErr := imp.run(ctx)
+	if runErr != nil && errors.Is(context.Cause(ctx), errImportCancelled) {
+		runErr = errImportCancelled
+	}
+
+	result := &ImportResult{
+		Applied:  imp.journal.applied(),
+		Warnings: imp.warnings,
+	}
+
+	if runErr == nil {
+		result.Summary = fmt.Sprintf("Import complete! (%d nodes, %d edges, %d pages)", imp.importedNodes, len(imp.edgesBySourceNode), imp.importedPages)
+		a.logger.Info(result.Summary, "changes", len(result.Applied))
+		emitProgress(result.Summary)
+		return imp, result, nil
+	}
+
+	a.logger.Error(runErr, "import failed, rolling back", "changes", len(result.Applied))
+	emitProgress(fmt.Sprintf("Import failed, rolling back %d changes...", len(result.Applied)))
+
+	// The import context may be the reason we failed, roll back with a fresh one
+	rollbackCtx, rollbackCancel := context.WithTimeout(context.Background(), importRollbackTimeout)
+	defer rollbackCancel()
+
+	result.Reverted, result.RollbackErrors = imp.journal.rollback(rollbackCtx, emitProgress)
+	result.RolledBack = true
+	result.Summary = result.rollbackReport()
+
+	for _, e := range result.RollbackErrors {
+		a.logger.Info("rollback error: " + e)
+	}
+	emitProgress(fmt.Sprintf("Import failed, rolled back %d of %d changes", len(result.Reverted), len(result.Applied)))
+
+	return imp, result, fmt.Errorf("import failed: %w\n%s", runErr, result.Summary)
+}
+
+// beginImport registers the cancel func of a starting import. Only one import runs at a time.
+func (a *App) beginImport(cancel context.CancelCauseFunc) error {
+	a.importMu.Lock()
+	defer a.importMu.Unlock()
+
+	if a.importCancel != nil {
+		return errors.New("another import is already in progress")
+	}
+	a.importCancel = cancel
+	return nil
+}
+
+func (a *App) endImport() {
+	a.importMu.Lock()
+	defer a.importMu.Unlock()
+
+	a.importCancel = nil
+}
+
+// CancelImport stops the running import. Changes it already made are rolled back.
+func (a *App) CancelImport() error {
+	a.importMu.Lock()
+	defer a.importMu.Unlock()
+
+	if a.importCancel == nil {
+		return errors.New("no import in progress")
+	}
+	a.logger.Info("cancelling import")
+	a.importCancel(errImportCancelled)
+	return nil
+}
+
+// parseProjectImport decodes and strictly validates a project export, or a bundle of
+// resource manifests converted into one.
+func (a *App) parseProjectImport(jsonData string) (*utils.ProjectExport, error) {
+	importData, err := decodeProjectImport(jsonData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import data: %v", err)
+	}
+
+	if importData.Version != utils.CurrentExportVersion {
+		return nil, fmt.Errorf("unsupported import version: %d", importData.Version)
+	}
+
+	// Strip runtime-internal schema fields that may have been left in the JSON
+	utils.StripSchemaInternalFields(importData)
+
+	// Validate import data strictly — block import if errors found
+	validationErrors, validationWarnings := utils.ValidateProjectImport(importData)
+	for _, w := range validationWarnings {
+		a.logger.Info("import warning: " + w)
+	}
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("import validation failed (%d errors):\n%s", len(validationErrors), strings.Join(validationErrors, "\n"))
+	}
+
+	return importData, nil
+}
+
+// run applies every part of the export, stopping at the first failure.
+func (imp *projectImporter) run(ctx context.Context) error {
+	steps := []func(context.Context) error{
+		imp.importDescription,
+		imp.loadExisting,
+		imp.importFlows,
+		imp.importNodes,
+		imp.waitForNodes,
+		imp.importEdges,
+		imp.verifyPortConfigs,
+		imp.importPages,
+		imp.importScenarios,
+	}
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := step(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warn records a non-fatal problem that is reported with the result.
+func (imp *projectImporter) warn(msg string, keysAndValues ...interface{}) {
+	imp.app.logger.Info(msg, keysAndValues...)
+
+	imp.mu.Lock()
+	imp.warnings = append(imp.warnings, msg+formatLogValues(keysAndValues))
+	imp.mu.Unlock()
+}
+
+// throttle waits for the rate limiter before an API request.
+func (imp *projectImporter) throttle(ctx context.Context) error {
+	if imp.limiter == nil {
+		return nil
+	}
+	return imp.limiter.Wait(ctx)
+}
+
+// parallel runs fn for indexes 0..n-1 on up to importWorkers goroutines.
+// The first error cancels the remaining calls and is returned.
+func (imp *projectImporter) parallel(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, importWorkers)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(ctx, i); err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// formatLogValues renders logr key/value pairs as " key=value ..." for warnings.
+func formatLogValues(keysAndValues []interface{}) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		b.WriteString(fmt.Sprintf(" %v=%v", keysAndValues[i], keysAndValues[i+1]))
+	}
+	return b.String()
+}
+
+// importDescription saves the project description from the import if present.
+func (imp *projectImporter) importDescription(ctx context.Context) error {
+	if imp.data.Description == "" {
+		return nil
+	}
+
+	project, err := imp.mgr.GetProject(ctx, imp.projectName, imp.namespace)
+	if err != nil {
+		return fmt.Errorf("unable to get project: %w", err)
+	}
+	previous := project.Spec.Description
+	if previous == imp.data.Description {
+		return nil
+	}
+
+	if err := imp.mgr.UpdateProjectDescription(ctx, imp.projectName, imp.namespace, imp.data.Description); err != nil {
+		return fmt.Errorf("unable to save project description: %w", err)
+	}
+	imp.journal.record(ImportChange{Kind: "project", Name: imp.projectName, Action: importActionUpdated}, func(ctx context.Context) error {
+		return imp.mgr.UpdateProjectDescription(ctx, imp.projectName, imp.namespace, previous)
+	})
+	return nil
+}
+
+// loadExisting reads the flows and nodes already in the project.
+func (imp *projectImporter) loadExisting(ctx context.Context) error {
+	existingFlows, err := imp.mgr.GetFlowList(ctx, imp.projectName)
+	if err != nil {
+		return fmt.Errorf("unable to get existing flows: %w", err)
+	}
+
+	imp.existingFlowNames = make(map[string]bool)
+	for _, flow := range existingFlows {
+		imp.existingFlowNames[flow.Name] = true
+	}
+
+	existingNodes, err := imp.mgr.GetProjectNodes(ctx, imp.projectName)
+	if err != nil {
+		return fmt.Errorf("unable to get existing nodes: %w", err)
+	}
+
+	imp.existingNodesMap = make(map[string]v1alpha1.TinyNode)
+	for _, node := range existingNodes {
+		imp.existingNodesMap[node.Name] = node
+	}
+	return nil
+}
+
+// importFlows creates flows that don't exist yet and maps old flow names to cluster names.
+func (imp *projectImporter) importFlows(ctx context.Context) error {
+	imp.progress(fmt.Sprintf("Creating flows... (%d flows)", len(imp.data.TinyFlows)))
+
+	imp.flowResourceNameMap = make(map[string]string)
+	for _, importFlow := range imp.data.TinyFlows {
+		imp.app.logger.Info("processing flow", "oldResourceName", importFlow.ResourceName, "displayName", importFlow.Name)
+		if imp.existingFlowNames[importFlow.ResourceName] {
+			imp.flowResourceNameMap[importFlow.ResourceName] = importFlow.ResourceName
+			imp.app.logger.Info("flow already exists", "resourceName", importFlow.ResourceName)
+			continue
+		}
+		newResourceName, err := imp.mgr.CreateFlow(ctx, imp.namespace, imp.projectName, importFlow.Name)
+		if err != nil {
+			return fmt.Errorf("failed to create flow %s: %w", importFlow.Name, err)
+		}
+		name := *newResourceName
+		imp.journal.record(ImportChange{Kind: "flow", Name: name, Title: importFlow.Name, Action: importActionCreated}, func(ctx context.Context) error {
+			return imp.mgr.DeleteFlow(ctx, name)
+		})
+		imp.flowResourceNameMap[importFlow.ResourceName] = name
+		imp.app.logger.Info("created new flow", "oldResourceName", importFlow.ResourceName, "newResourceName", name)
+	}
+	imp.app.logger.Info("flow mapping complete", "mappings", imp.flowResourceNameMap)
+	return nil
+}
+
+// importNodes creates new nodes and updates nodes that already exist, using a bounded worker pool.
+func (imp *projectImporter) importNodes(ctx context.Context) error {
+	imp.nodeIDMap = make(map[string]string)
+
+	imp.progress(fmt.Sprintf("Importing nodes... (%d elements)", len(imp.data.Elements)))
+
+	type nodeTask struct {
+		oldNodeID string
+		elem      map[string]interface{}
+		existing  *v1alpha1.TinyNode // set when the node is updated
+		node      *v1alpha1.TinyNode // set when the node is created
+	}
+
+	var tasks []nodeTask
+	for _, elem := range imp.data.Elements {
+		elemType, _ := elem["type"].(string)
+		if elemType == "edge" || elemType == "tinyEdge" || elemType == "" {
+			continue
+		}
+
+		oldNodeID, _ := elem["id"].(string)
+		if oldNodeID == "" {
+			continue
+		}
+
+		// Check if node already exists — update it instead of skipping
+		if existing, exists := imp.existingNodesMap[oldNodeID]; exists {
+			imp.nodeIDMap[oldNodeID] = oldNodeID
+			if imp.edgeOnlyNodes[oldNodeID] {
+				// Belongs to another flow: keep its settings and annotations, edges are applied later
+				continue
+			}
+			tasks = append(tasks, nodeTask{oldNodeID: oldNodeID, elem: elem, existing: existing.DeepCopy()})
+			continue
+		}
+
+		node, skipReason := imp.buildNode(oldNodeID, elem)
+		if node == nil {
+			imp.warn("skipping node - "+skipReason, "nodeID", oldNodeID)
+			continue
+		}
+		tasks = append(tasks, nodeTask{oldNodeID: oldNodeID, elem: elem, node: node})
+	}
+
+	total := len(tasks)
+	done := 0
+	return imp.parallel(ctx, total, func(ctx context.Context, i int) error {
+		t := tasks[i]
+		var err error
+		if t.existing != nil {
+			err = imp.updateExistingNode(ctx, t.existing, t.elem)
+		} else {
+			err = imp.createNode(ctx, t.oldNodeID, t.node)
+		}
+		if err != nil {
+			return err
+		}
+
+		imp.mu.Lock()
+		done++
+		msg := fmt.Sprintf("Imported nodes: %d/%d", done, total)
+		imp.mu.Unlock()
+		imp.progress(msg)
+		return nil
+	})
+}
+
+// createNode creates a node built from the import and journals its deletion.
+func (imp *projectImporter) createNode(ctx context.Context, oldNodeID string, node *v1alpha1.TinyNode) error {
+	if err := imp.throttle(ctx); err != nil {
+		return err
+	}
+	// Use async CreateNode - don't wait for sync (too slow)
+	if err := imp.mgr.CreateNode(ctx, node); err != nil {
+		return fmt.Errorf("failed to create node %s (%s): %w", node.Name, node.Spec.Component, err)
+	}
+	created := node.DeepCopy()
+	imp.journal.record(ImportChange{Kind: "node", Name: node.Name, Title: node.Annotations[v1alpha1.NodeLabelAnnotation], Action: importActionCreated}, func(ctx context.Context) error {
+		return imp.mgr.DeleteNode(ctx, created)
+	})
+	imp.app.logger.Info("imported node", "component", node.Spec.Component, "name", node.Name, "portsCount", len(node.Spec.Ports))
+
+	imp.mu.Lock()
+	imp.nodeIDMap[oldNodeID] = node.Name
+	imp.createdNodes = append(imp.createdNodes, node.Name)
+	imp.importedNodes++
+	imp.mu.Unlock()
+	return nil
+}
+
+// buildNode turns an exported node element into a new TinyNode. If the element
+// can't be imported it returns nil and the reason.
+func (imp *projectImporter) buildNode(oldNodeID string, elem map[string]interface{}) (*v1alpha1.TinyNode, string) {
+	// Get flow for this node
+	flowName, _ := elem["flow"].(string)
+	if flowName == "" {
+		return nil, "no flow name"
+	}
+	newFlowName := imp.flowResourceNameMap[flowName]
+	if newFlowName == "" {
+		return nil, fmt.Sprintf("flow %s not in map", flowName)
+	}
+	imp.app.logger.Info("creating node", "nodeID", oldNodeID, "oldFlow", flowName, "newFlow", newFlowName)
+
+	data, _ := elem["data"].(map[string]interface{})
+	if data == nil {
+		return nil, "no node data"
+	}
+
+	component, _ := data["component"].(string)
+	module, _ := data["module"].(string)
+	if component == "" || module == "" {
+		return nil, "no component or module"
+	}
+
+	// Get position
+	position, _ := elem["position"].(map[string]interface{})
+	posX, posY := 0, 0
+	if position != nil {
+		if x, ok := position["x"].(float64); ok {
+			posX = int(x)
+		}
+		if y, ok := position["y"].(float64); ok {
+			posY = int(y)
+		}
+	}
+
+	// Create node with proper naming: {hash}.{module}.{component}-{suffix}
+	// Use suffix from original node ID if available to preserve uniqueness
+	// when multiple nodes share the same component type (e.g., two Tickers)
+	nodeGenerateName := utils.GetNodeGenerateName(imp.projectName, newFlowName, module, component)
+	suffix := strconv.FormatInt(time.Now().UnixNano(), 36)[:5]
+	if imp.freshNodeNames {
+		suffix = uuid.New().String()[:5]
+	} else if idx := strings.LastIndex(oldNodeID, "-"); idx >= 0 && idx < len(oldNodeID)-1 {
+		suffix = oldNodeID[idx+1:]
+	}
+	nodeName := nodeGenerateName + suffix
+
+	// Get label from data
+	label, _ := data["label"].(string)
+	if label == "" {
+		label = component
+	}
+
+	// Get spin value
+	spin := 0
+	if spinVal, ok := data["spin"].(float64); ok {
+		spin = int(spinVal)
+	}
+
+	// Get dashboard flag
+	dashboard, _ := data["dashboard"].(string)
+
+	labels := map[string]string{
+		v1alpha1.FlowNameLabel:    newFlowName,
+		v1alpha1.ProjectNameLabel: imp.projectName,
+	}
+	if dashboard == "true" {
+		labels[v1alpha1.DashboardLabel] = "true"
+	}
+
+	// Extract port configurations from handles
+	ports := imp.handlePortConfigs(component, data)
+
+	annotations := map[string]string{
+		v1alpha1.ComponentPosXAnnotation:    strconv.Itoa(posX),
+		v1alpha1.ComponentPosYAnnotation:    strconv.Itoa(posY),
+		v1alpha1.ComponentPosSpinAnnotation: strconv.Itoa(spin),
+		v1alpha1.NodeLabelAnnotation:        label,
+	}
+
+	if sharedWith, _ := data["shared_with_flows"].(string); sharedWith != "" {
+		annotations[v1alpha1.SharedWithFlowsAnnotation] = utils.ResolveSharedFlows(sharedWith, imp.flowResourceNameMap)
+	}
+
+	return &v1alpha1.TinyNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        nodeName,
+			Namespace:   imp.namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: v1alpha1.TinyNodeSpec{
+			Module:    module,
+			Component: component,
+			Ports:     ports,
+		},
+	}, ""
+}
+
+// handlePortConfigs extracts port configurations from the handles of an exported node.
+func (imp *projectImporter) handlePortConfigs(component string, data map[string]interface{}) []v1alpha1.TinyNodePortConfig {
+	handles, ok := data["handles"].([]interface{})
+	if !ok {
+		imp.app.logger.Info("no handles found for node", "component", component, "dataKeys", getMapKeys(data))
+		return nil
+	}
+
+	imp.app.logger.Info("processing handles for node", "component", component, "handleCount", len(handles))
+
+	var ports []v1alpha1.TinyNodePortConfig
+	for _, h := range handles {
+		handle, ok := h.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		portID, _ := handle["id"].(string)
+		if portID == "" {
+			continue
+		}
+
+		// Skip source ports — they are runtime-generated and their bare
+		// configurable definitions would overwrite richer definitions from
+		// target ports (like _settings) in GetConfigurableDefinitions()
+		if portType, _ := handle["type"].(string); portType == "source" {
+			continue
+		}
+
+		// Get configuration and schema from handle
+		config := handle["configuration"]
+		schema := handle["schema"]
+
+		// Marshal config to JSON for storage
+		var configBytes []byte
+		if config != nil {
+			var err error
+			configBytes, err = json.Marshal(config)
+			if err != nil {
+				imp.app.logger.Error(err, "failed to marshal port config", "port", portID)
+				continue
+			}
+			// Check if empty
+			if string(configBytes) == "{}" || string(configBytes) == "null" {
+				configBytes = nil
+			}
+		}
+
+		// Marshal schema to JSON for storage
+		var schemaBytes []byte
+		if schema != nil {
+			var err error
+			schemaBytes, err = json.Marshal(schema)
+			if err != nil {
+				imp.app.logger.Error(err, "failed to marshal port schema", "port", portID)
+			}
+		}
+
+		// Skip if both config and schema are empty
+		if len(configBytes) == 0 && len(schemaBytes) == 0 {
+			continue
+		}
+
+		ports = append(ports, v1alpha1.TinyNodePortConfig{
+			Port:          portID,
+			Configuration: configBytes,
+			Schema:        schemaBytes,
+		})
+		imp.app.logger.Info("added port config", "port", portID, "configLen", len(configBytes), "schemaLen", len(schemaBytes))
+	}
+	return ports
+}
+
+// applyImportedNode updates an existing node in memory with imported data
+// (position, label, shared flows, port configs from handles). Returns false if
+// the element carries no node data.
+func (imp *projectImporter) applyImportedNode(node *v1alpha1.TinyNode, elem map[string]interface{}) bool {
+	data, _ := elem["data"].(map[string]interface{})
+	if data == nil {
+		imp.app.logger.Info("import element has no data, skipping update", "node", node.Name)
+		return false
+	}
+
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+
+	// Update position
+	if position, _ := elem["position"].(map[string]interface{}); position != nil {
+		if x, ok := position["x"].(float64); ok {
+			node.Annotations[v1alpha1.ComponentPosXAnnotation] = strconv.Itoa(int(x))
+		}
+		if y, ok := position["y"].(float64); ok {
+			node.Annotations[v1alpha1.ComponentPosYAnnotation] = strconv.Itoa(int(y))
+		}
+	}
+
+	// Update spin
+	if spinVal, ok := data["spin"].(float64); ok {
+		node.Annotations[v1alpha1.ComponentPosSpinAnnotation] = strconv.Itoa(int(spinVal))
+	}
+
+	// Update label
+	if label, ok := data["label"].(string); ok && label != "" {
+		node.Annotations[v1alpha1.NodeLabelAnnotation] = label
+	}
+
+	// Update shared_with_flows annotation (resolve import flow names to cluster names)
+	if sharedWith, _ := data["shared_with_flows"].(string); sharedWith != "" {
+		node.Annotations[v1alpha1.SharedWithFlowsAnnotation] = utils.ResolveSharedFlows(sharedWith, imp.flowResourceNameMap)
+	} else {
+		delete(node.Annotations, v1alpha1.SharedWithFlowsAnnotation)
+	}
+
+	// Rebuild port configs from handles (same logic as node creation)
+	handlePorts := imp.handlePortConfigs(node.Spec.Component, data)
+
+	// Replace handle-level port configs (From=""), keep edge-level port configs (From!="")
+	var edgePorts []v1alpha1.TinyNodePortConfig
+	for _, pc := range node.Spec.Ports {
+		if pc.From != "" {
+			edgePorts = append(edgePorts, pc)
+		}
+	}
+	node.Spec.Ports = append(handlePorts, edgePorts...)
+	return true
+}
+
+// updateExistingNode updates an existing node with imported data and journals its previous state.
+// On conflict the import is re-applied to the latest version of the node.
+func (imp *projectImporter) updateExistingNode(ctx context.Context, node *v1alpha1.TinyNode, elem map[string]interface{}) error {
+	var before *v1alpha1.TinyNode
+	first := true
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			if err := imp.throttle(ctx); err != nil {
+				return err
+			}
+			latest, err := imp.mgr.GetNode(ctx, node.Name, imp.namespace)
+			if err != nil {
+				return err
+			}
+			node = latest
+		}
+		first = false
+
+		before = node.DeepCopy()
+		if !imp.applyImportedNode(node, elem) {
+			before = nil
+			return nil
+		}
+		if err := imp.throttle(ctx); err != nil {
+			return err
+		}
+		return imp.mgr.UpdateNode(ctx, node)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update existing node %s: %w", node.Name, err)
+	}
+	if before == nil {
+		return nil
+	}
+	imp.recordNodeUpdate(before)
+	imp.app.logger.Info("updated existing node", "node", node.Name, "ports", len(node.Spec.Ports))
+	return nil
+}
+
+// recordNodeUpdate journals a node update so rollback restores the given previous state.
+func (imp *projectImporter) recordNodeUpdate(before *v1alpha1.TinyNode) {
+	imp.journal.record(ImportChange{Kind: "node", Name: before.Name, Title: before.Annotations[v1alpha1.NodeLabelAnnotation], Action: importActionUpdated}, func(ctx context.Context) error {
+		current, err := imp.mgr.GetNode(ctx, before.Name, imp.namespace)
+		if err != nil {
+			return err
+		}
+		current.Labels = before.Labels
+		current.Annotations = before.Annotations
+		current.Spec = before.Spec
+		return imp.mgr.UpdateNode(ctx, current)
+	})
+}
+
+// waitForNodes waits until the controller has reconciled every created node (it has
+// written the node's status) before edges are added. Nodes that exist but are still not
+// reconciled at the deadline are reported as warnings; missing nodes fail the import.
+func (imp *projectImporter) waitForNodes(ctx context.Context) error {
+	if len(imp.createdNodes) == 0 {
+		return nil
+	}
+	total := len(imp.createdNodes)
+	imp.progress(fmt.Sprintf("Waiting for %d nodes to be ready...", total))
+	imp.app.logger.Info("waiting for nodes to be ready before adding edges", "nodeCount", total)
+
+	pending := make(map[string]bool, total)
+	for _, name := range imp.createdNodes {
+		pending[name] = true
+	}
+	found := make(map[string]bool, total)
+
+	err := wait.PollUntilContextTimeout(ctx, importPollInterval, importNodeReadyTimeout, true, func(ctx context.Context) (bool, error) {
+		nodes, err := imp.mgr.GetProjectNodes(ctx, imp.projectName)
+		if err != nil {
+			// Transient API errors are retried until the deadline
+			imp.app.logger.Error(err, "unable to list nodes while waiting for readiness")
+			return false, nil
+		}
+		for _, node := range nodes {
+			if !pending[node.Name] {
+				continue
+			}
+			found[node.Name] = true
+			if !nodeReconciled(&node) {
+				continue
+			}
+			delete(pending, node.Name)
+			imp.progress(fmt.Sprintf("Node %s is ready (%d/%d)", nodeTitle(&node), total-len(pending), total))
+		}
+		return len(pending) == 0, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if !wait.Interrupted(err) || ctx.Err() != nil {
+		return err
+	}
+
+	var missing []string
+	for name := range pending {
+		if !found[name] {
+			missing = append(missing, name)
+			continue
+		}
+		imp.warn("node not reconciled before deadline, continuing", "node", name)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("created nodes not found after %s: %s", importNodeReadyTimeout, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// nodeReconciled reports whether the controller has reconciled a node. It sets the status
+// on every reconcile, also for components without ports, so an empty status means the node
+// hasn't been picked up yet.
+func nodeReconciled(node *v1alpha1.TinyNode) bool {
+	return !equality.Semantic.DeepEqual(node.Status, v1alpha1.TinyNodeStatus{})
+}
+
+// nodeTitle returns the node label for progress messages, falling back to its name.
+func nodeTitle(node *v1alpha1.TinyNode) string {
+	if label := node.Annotations[v1alpha1.NodeLabelAnnotation]; label != "" {
+		return label
+	}
+	return node.Name
+}
+
+// collectEdges translates imported edges to cluster node names, grouping edges by
+// source node and edge configurations (port configs) by target node.
+func (imp *projectImporter) collectEdges() {
+	// Import edges - collect all updates per node to do a single update
+	// This prevents race conditions where controller reconciliation between updates could reset data
+	imp.edgesBySourceNode = make(map[string][]v1alpha1.TinyNodeEdge)
+	imp.portConfigsByTarget = make(map[string][]v1alpha1.TinyNodePortConfig)
+
+	for _, elem := range imp.data.Elements {
+		elemType, _ := elem["type"].(string)
+		if elemType != "edge" && elemType != "tinyEdge" {
+			continue
+		}
+
+		oldSourceID, _ := elem["source"].(string)
+		sourceHandle, _ := elem["sourceHandle"].(string)
+		oldTargetID, _ := elem["target"].(string)
+		targetHandle, _ := elem["targetHandle"].(string)
+		flowName, _ := elem["flow"].(string)
+
+		// Log the raw edge data for debugging
+		edgeDataRaw, _ := json.Marshal(elem["data"])
+		imp.app.logger.Info("processing edge", "source", oldSourceID, "sourceHandle", sourceHandle, "target", oldTargetID, "targetHandle", targetHandle, "dataRaw", string(edgeDataRaw))
+
+		// Translate old IDs to new names
+		newSourceName := imp.nodeIDMap[oldSourceID]
+		newTargetName := imp.nodeIDMap[oldTargetID]
+		newFlowName := imp.flowResourceNameMap[flowName]
+
+		if newSourceName == "" || newTargetName == "" || newFlowName == "" {
+			imp.warn("skipping edge - missing node mapping", "source", oldSourceID, "target", oldTargetID)
+			continue
+		}
+
+		// Generate new edge ID with new node names: {source}_{sourcePort}-{target}_{targetPort}
+		newEdgeID := fmt.Sprintf("%s_%s-%s_%s", newSourceName, sourceHandle, newTargetName, targetHandle)
+
+		edge := v1alpha1.TinyNodeEdge{
+			ID:     newEdgeID,
+			Port:   sourceHandle,
+			To:     newTargetName + ":" + targetHandle,
+			FlowID: newFlowName,
+		}
+		imp.edgesBySourceNode[newSourceName] = append(imp.edgesBySourceNode[newSourceName], edge)
+
+		// Extract edge configuration from elem["data"]["configuration"]
+		// This needs to be added as a port config on the TARGET node
+		edgeData, hasData := elem["data"].(map[string]interface{})
+		if !hasData {
+			imp.app.logger.Info("edge has no data map", "edge", newEdgeID)
+			continue
+		}
+
+		var configBytes []byte
+		var schemaBytes []byte
+
+		config := edgeData["configuration"]
+		imp.app.logger.Info("edge configuration check", "edge", newEdgeID, "hasConfig", config != nil, "configType", fmt.Sprintf("%T", config))
+
+		if config != nil {
+			var err error
+			configBytes, err = json.Marshal(config)
+			if err != nil {
+				imp.app.logger.Error(err, "failed to marshal edge config", "edge", newEdgeID)
+			} else {
+				imp.app.logger.Info("edge config marshaled", "edge", newEdgeID, "configLen", len(configBytes), "configPreview", truncateString(string(configBytes), 200))
+			}
+		}
+
+		if edgeSchema := edgeData["schema"]; edgeSchema != nil {
+			var err error
+			schemaBytes, err = json.Marshal(edgeSchema)
+			if err != nil {
+				imp.app.logger.Error(err, "failed to marshal edge schema", "edge", newEdgeID)
+			}
+		}
+
+		if len(configBytes) > 0 {
+			sourcePortFullName := newSourceName + ":" + sourceHandle
+			portConfig := v1alpha1.TinyNodePortConfig{
+				From:          sourcePortFullName,
+				Port:          targetHandle,
+				Configuration: configBytes,
+				Schema:        schemaBytes,
+				FlowID:        newFlowName,
+			}
+			imp.portConfigsByTarget[newTargetName] = append(imp.portConfigsByTarget[newTargetName], portConfig)
+			imp.app.logger.Info("prepared edge port config", "target", newTargetName, "port", targetHandle, "from", sourcePortFullName, "configLen", len(configBytes), "schemaLen", len(schemaBytes))
+		}
+	}
+}
+
+// nodesToUpdate returns the names of all nodes that get imported edges or edge configurations,
+// and when pruning, of existing nodes that have edges or edge configurations in imported flows.
+func (imp *projectImporter) nodesToUpdate() map[string]bool {
+	allNodesToUpdate := make(map[string]bool)
+	for nodeName := range imp.edgesBySourceNode {
+		allNodesToUpdate[nodeName] = true
+	}
+	for nodeName := range imp.portConfigsByTarget {
+		allNodesToUpdate[nodeName] = true
+	}
+	if imp.pruneFlowEdges {
+		importedFlowNames := imp.importedFlowNames()
+		for name, node := range imp.existingNodesMap {
+			if node.DeletionTimestamp != nil {
+				continue
+			}
+			for _, e := range node.Spec.Edges {
+				if importedFlowNames[e.FlowID] {
+					allNodesToUpdate[name] = true
+				}
+			}
+			for _, pc := range node.Spec.Ports {
+				if pc.From != "" && importedFlowNames[pc.FlowID] {
+					allNodesToUpdate[name] = true
+				}
+			}
+		}
+	}
+	return allNodesToUpdate
+}
+
+// importedFlowNames returns the cluster names of all flows in the import, used to
+// replace edges and edge configurations that earlier imports left behind.
+func (imp *projectImporter) importedFlowNames() map[string]bool {
+	importedFlowNames := make(map[string]bool)
+	for oldName, name := range imp.flowResourceNameMap {
+		// Only some nodes of dependency flows are imported, their other edges must stay
+		if imp.dependencyFlows[oldName] {
+			continue
+		}
+		importedFlowNames[name] = true
+	}
+	return importedFlowNames
+}
+
+// applyImportedEdges replaces edges and edge configurations of imported flows on a node in memory.
+func (imp *projectImporter) applyImportedEdges(node *v1alpha1.TinyNode, importedFlowNames map[string]bool) {
+	// Remove old edges belonging to imported flows, then append new ones.
+	// This prevents stale edges from accumulating when node IDs change across re-imports.
+	if edges, ok := imp.edgesBySourceNode[node.Name]; ok || imp.pruneFlowEdges {
+		node.Spec.Edges = utils.ReplaceFlowEdges(node.Spec.Edges, importedFlowNames, edges)
+		imp.app.logger.Info("replaced edges on node", "node", node.Name, "importedEdgeCount", len(edges))
+	}
+
+	// Remove old edge port configs belonging to imported flows, then append new ones.
+	if portConfigs, ok := imp.portConfigsByTarget[node.Name]; ok || imp.pruneFlowEdges {
+		node.Spec.Ports = utils.ReplaceFlowPortConfigs(node.Spec.Ports, importedFlowNames, portConfigs)
+		imp.app.logger.Info("replaced port configs on node", "node", node.Name, "importedPortConfigCount", len(portConfigs))
+	}
+}
+
+// importEdges adds imported edges to source nodes and edge configurations to target nodes.
+func (imp *projectImporter) importEdges(ctx context.Context) error {
+	imp.progress("Processing edges...")
+
+	imp.collectEdges()
+	allNodesToUpdate := imp.nodesToUpdate()
+	importedFlowNames := imp.importedFlowNames()
+
+	imp.app.logger.Info("edge port configs summary",
+		"totalEdges", len(imp.edgesBySourceNode),
+		"totalPortConfigs", len(imp.portConfigsByTarget),
+		"nodesToUpdate", len(allNodesToUpdate))
+
+	imp.progress(fmt.Sprintf("Updating %d nodes with edge configurations...", len(allNodesToUpdate)))
+
+	// Update each node ONCE with all its changes (both edges and port configs)
+	// This prevents race conditions with controller reconciliation
+	nodeNames := make([]string, 0, len(allNodesToUpdate))
+	for nodeName := range allNodesToUpdate {
+		nodeNames = append(nodeNames, nodeName)
+	}
+
+	total := len(nodeNames)
+	done := 0
+	return imp.parallel(ctx, total, func(ctx context.Context, i int) error {
+		if err := imp.updateNodeEdges(ctx, nodeNames[i], importedFlowNames); err != nil {
+			return err
+		}
+
+		imp.mu.Lock()
+		done++
+		msg := fmt.Sprintf("Updated edges: %d/%d nodes", done, total)
+		imp.mu.Unlock()
+		imp.progress(msg)
+		return nil
+	})
+}
+
+// updateNodeEdges applies imported edges and edge configurations to a node, retrying
+// on conflict with the latest version of the node.
+func (imp *projectImporter) updateNodeEdges(ctx context.Context, nodeName string, importedFlowNames map[string]bool) error {
+	var before, node *v1alpha1.TinyNode
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := imp.throttle(ctx); err != nil {
+			return err
+		}
+		var err error
+		node, err = imp.mgr.GetNode(ctx, nodeName, imp.namespace)
+		if err != nil {
+			return fmt.Errorf("failed to get node %s for update: %w", nodeName, err)
+		}
+		before = node.DeepCopy()
+
+		imp.applyImportedEdges(node, importedFlowNames)
+
+		if err := imp.throttle(ctx); err != nil {
+			return err
+		}
+		return imp.mgr.UpdateNode(ctx, node)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update node %s with edges: %w", nodeName, err)
+	}
+	imp.recordNodeUpdate(before)
+	imp.app.logger.Info("updated node successfully", "node", nodeName,
+		"existingEdges", len(before.Spec.Edges), "totalEdges", len(node.Spec.Edges),
+		"existingPorts", len(before.Spec.Ports), "totalPorts", len(node.Spec.Ports))
+	return nil
+}
+
+// verifyPortConfigs waits until every imported edge configuration can be read back
+// from its target node. Configurations still missing at the deadline are reported as warnings.
+func (imp *projectImporter) verifyPortConfigs(ctx context.Context) error {
+	if len(imp.portConfigsByTarget) == 0 {
+		return nil
+	}
+	total := len(imp.portConfigsByTarget)
+	imp.progress(fmt.Sprintf("Verifying edge configurations on %d nodes...", total))
+	imp.app.logger.Info("verifying port configs were persisted...")
+
+	pending := make(map[string]bool, total)
+	for nodeName := range imp.portConfigsByTarget {
+		pending[nodeName] = true
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, importPollInterval, importVerifyTimeout, true, func(ctx context.Context) (bool, error) {
+		for nodeName := range pending {
+			verifyNode, err := imp.mgr.GetNode(ctx, nodeName, imp.namespace)
+			if err != nil {
+				imp.app.logger.Error(err, "failed to verify node", "node", nodeName)
+				continue
+			}
+			if missing := missingPortConfigs(verifyNode, imp.portConfigsByTarget[nodeName]); len(missing) > 0 {
+				imp.app.logger.Info("edge port configs not persisted yet", "node", nodeName, "missing", missing)
+				continue
+			}
+			delete(pending, nodeName)
+			imp.app.logger.Info("verification result", "node", nodeName, "totalPorts", len(verifyNode.Spec.Ports))
+			imp.progress(fmt.Sprintf("Edge configurations on %s verified (%d/%d)", nodeTitle(verifyNode), total-len(pending), total))
+		}
+		return len(pending) == 0, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if !wait.Interrupted(err) || ctx.Err() != nil {
+		return err
+	}
+	for nodeName := range pending {
+		imp.warn("edge configurations not persisted before deadline", "node", nodeName)
+	}
+	return nil
+}
+
+// missingPortConfigs returns the "from->port" keys of expected edge configurations the node doesn't have.
+func missingPortConfigs(node *v1alpha1.TinyNode, expected []v1alpha1.TinyNodePortConfig) []string {
+	have := make(map[string]bool, len(node.Spec.Ports))
+	for _, pc := range node.Spec.Ports {
+		if pc.From != "" {
+			have[portConfigKey(pc)] = true
+		}
+	}
+	var missing []string
+	for _, pc := range expected {
+		if !have[portConfigKey(pc)] {
+			missing = append(missing, portConfigKey(pc))
+		}
+	}
+	return missing
+}
+
+// importPages creates dashboard pages that don't exist yet, with their widgets.
+func (imp *projectImporter) importPages(ctx context.Context) error {
+	if len(imp.data.Pages) == 0 {
+		return nil
+	}
+	imp.progress(fmt.Sprintf("Creating dashboard pages... (%d pages)", len(imp.data.Pages)))
+
+	existingPages, err := imp.mgr.GetProjectPageWidgets(ctx, imp.projectName)
+	if err != nil {
+		return fmt.Errorf("unable to get existing pages: %w", err)
+	}
+	imp.app.logger.Info("existing pages before import", "count", len(existingPages), "projectName", imp.projectName)
+
+	// Build map of existing page titles to avoid duplicates
+	existingPageTitles := make(map[string]bool)
+	for _, page := range existingPages {
+		title := page.Annotations[v1alpha1.PageTitleAnnotation]
+		if title == "" {
+			title = page.Name
+		}
+		existingPageTitles[title] = true
+	}
+
+	for _, importPage := range imp.data.Pages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Check by title, not by resource name (resource names are regenerated)
+		if existingPageTitles[importPage.Title] {
+			imp.app.logger.Info("skipping page - already exists", "title", importPage.Title)
+			continue
+		}
+
+		widgets, _ := imp.translateWidgets(importPage)
+
+		// Create the page
+		imp.app.logger.Info("creating page", "title", importPage.Title, "project", imp.projectName, "namespace", imp.namespace, "sortIdx", importPage.SortIdx)
+		newPageName, err := imp.mgr.CreatePage(ctx, importPage.Title, imp.projectName, imp.namespace, importPage.SortIdx)
+		if err != nil {
+			return fmt.Errorf("failed to create page %s: %w", importPage.Title, err)
+		}
+		if newPageName == nil {
+			return fmt.Errorf("failed to create page %s: no resource name returned", importPage.Title)
+		}
+		pageName := *newPageName
+		imp.journal.record(ImportChange{Kind: "page", Name: pageName, Title: importPage.Title, Action: importActionCreated}, func(ctx context.Context) error {
+			return imp.mgr.DeletePage(ctx, &v1alpha1.TinyWidgetPage{
+				ObjectMeta: metav1.ObjectMeta{Name: pageName, Namespace: imp.namespace},
+			})
+		})
+		imp.importedPages++
+		imp.app.logger.Info("page created successfully", "title", importPage.Title, "resourceName", pageName)
+
+		if len(widgets) == 0 {
+			imp.app.logger.Info("no widgets to add to page", "page", pageName)
+			continue
+		}
+
+		page, err := imp.waitForPage(ctx, pageName)
+		if err != nil {
+			return err
+		}
+		if err := imp.setPageWidgets(ctx, page, widgets); err != nil {
+			return err
+		}
+		imp.progress(fmt.Sprintf("Page %s is ready with %d widgets", importPage.Title, len(widgets)))
+	}
+
+	// Final verification
+	finalPages, _ := imp.mgr.GetProjectPageWidgets(ctx, imp.projectName)
+	imp.app.logger.Info("pages after import complete", "count", len(finalPages), "projectName", imp.projectName)
+	return nil
+}
+
+// translateWidgets builds page widgets with port references translated to imported node names.
+// Widgets that can't be translated are returned in skipped, keyed by widget port with the reason.
+func (imp *projectImporter) translateWidgets(importPage utils.ExportPage) (widgets []v1alpha1.TinyWidget, skipped map[string]string) {
+	skipped = make(map[string]string)
+	for _, importWidget := range importPage.Widgets {
+		// Translate port reference: "oldNodeID:portName" -> "newNodeName:portName"
+		portParts := strings.SplitN(importWidget.Port, ":", 2)
+		if len(portParts) != 2 {
+			imp.warn("skipping widget - invalid port format", "port", importWidget.Port)
+			skipped[importWidget.Port] = "invalid port format"
+			continue
+		}
+		oldNodeID := portParts[0]
+		portName := portParts[1]
+
+		newNodeName := imp.nodeIDMap[oldNodeID]
+		if newNodeName == "" {
+			imp.warn("skipping widget - node not found in map", "page", importPage.Title, "oldNodeID", oldNodeID)
+			skipped[importWidget.Port] = "node not imported"
+			continue
+		}
+
+		newPort := newNodeName + ":" + portName
+		widget := v1alpha1.TinyWidget{
+			Port:  newPort,
+			Name:  importWidget.Name,
+			GridX: importWidget.GridX,
+			GridY: importWidget.GridY,
+			GridW: importWidget.GridW,
+			GridH: importWidget.GridH,
+		}
+		if len(importWidget.SchemaPatch) > 0 {
+			widget.SchemaPatch = []byte(importWidget.SchemaPatch)
+		}
+		widgets = append(widgets, widget)
+		imp.app.logger.Info("translated widget port", "oldPort", importWidget.Port, "newPort", newPort, "widgetName", importWidget.Name)
+	}
+	return widgets, skipped
+}
+
+// waitForPage polls until a created page is queryable and returns it.
+func (imp *projectImporter) waitForPage(ctx context.Context, pageName string) (*v1alpha1.TinyWidgetPage, error) {
+	var page *v1alpha1.TinyWidgetPage
+	err := wait.PollUntilContextTimeout(ctx, importPollInterval, importPageReadyTimeout, true, func(ctx context.Context) (bool, error) {
+		allPages, err := imp.mgr.GetProjectPageWidgets(ctx, imp.projectName)
+		if err != nil {
+			imp.app.logger.Error(err, "unable to get pages while waiting for page", "page", pageName)
+			return false, nil
+		}
+		for i := range allPages {
+			if allPages[i].Name == pageName {
+				page = &allPages[i]
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		if wait.Interrupted(err) && ctx.Err() == nil {
+			return nil, fmt.Errorf("created page %s not found after %s", pageName, importPageReadyTimeout)
+		}
+		return nil, err
+	}
+	return page, nil
+}
+
+// setPageWidgets stores widgets on a page created by this import.
+func (imp *projectImporter) setPageWidgets(ctx context.Context, page *v1alpha1.TinyWidgetPage, widgets []v1alpha1.TinyWidget) error {
+	page.Spec.Widgets = widgets
+	if err := imp.mgr.UpdatePage(ctx, page); err != nil {
+		return fmt.Errorf("failed to update page %s with widgets: %w", page.Name, err)
+	}
+	imp.app.logger.Info("added widgets to page", "page", page.Name, "widgetCount", len(widgets))
+	return nil
+}
+
+// importScenarios creates scenarios that don't exist yet (by name).
+func (imp *projectImporter) importScenarios(ctx context.Context) error {
+	if len(imp.data.Scenarios) == 0 {
+		return nil
+	}
+	imp.progress(fmt.Sprintf("Creating scenarios... (%d scenarios)", len(imp.data.Scenarios)))
+
+	existingScenarioNames := make(map[string]bool)
+	if existing, err := imp.mgr.GetProjectScenarios(ctx, imp.projectName); err == nil {
+		for _, s := range existing {
+			name := s.Annotations[v1alpha1.ScenarioNameAnnotation]
+			if name == "" {
+				name = s.Name
+			}
+			existingScenarioNames[name] = true
+		}
+	}
+
+	for _, importScenario := range imp.data.Scenarios {
+		if existingScenarioNames[importScenario.Name] {
+			imp.app.logger.Info("skipping scenario - already exists", "name", importScenario.Name)
+			continue
+		}
+		scenario, err := imp.mgr.CreateScenario(ctx, importScenario.Name, imp.projectName)
+		if err != nil {
+			return fmt.Errorf("failed to create scenario %s: %w", importScenario.Name, err)
+		}
+		created := scenario.DeepCopy()
+		imp.journal.record(ImportChange{Kind: "scenario", Name: scenario.Name, Title: importScenario.Name, Action: importActionCreated}, func(ctx context.Context) error {
+			return imp.mgr.GetK8sClient().Delete(ctx, created)
+		})
+
+		var ports []v1alpha1.ScenarioPortData
+		for _, p := range importScenario.Ports {
+			port := p.Port
+			// Remap port node references using nodeIDMap. A port of a node that wasn't
+			// imported would point at whatever node has the old ID, so it is dropped.
+			parts := strings.SplitN(port, ":", 2)
+			if len(parts) == 2 {
+				newName, ok := imp.nodeIDMap[parts[0]]
+				if !ok {
+					imp.warn("dropping scenario port - node not imported", "scenario", importScenario.Name, "port", port)
+					continue
+				}
+				port = newName + ":" + parts[1]
+			}
+			ports = append(ports, v1alpha1.ScenarioPortData{
+				Port: port,
+				Data: p.Data,
+			})
+		}
+		scenario.Spec.Ports = ports
+		if err := imp.mgr.UpdateScenario(ctx, scenario); err != nil {
+			return fmt.Errorf("failed to update scenario %s: %w", importScenario.Name, err)
+		}
+	}
+	return nil
+}
@@ -8,18 +8,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 
 	jsonpatchapply "github.com/evanphx/json-patch"
 	"github.com/tiny-systems/module/api/v1alpha1"
 	"github.com/tiny-systems/module/pkg/resource"
 	"github.com/tiny-systems/module/pkg/utils"
-	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 	"gomodules.xyz/jsonpatch/v2"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Project struct {
@@ -135,22 +130,7 @@ func (a *App) GetProjects(contextName string, namespace string) ([]Project, erro
   a.logger.Info("getting projects", "context", contextName, "namespace", namespace)
   var projectsApi []Project
 
-  config, err := loadContextConfig(contextName)
-  if err != nil {
-    return nil, err
-  }
-
-  scheme := runtime.NewScheme()
-  if err := v1alpha1.AddToScheme(scheme); err != nil {
-    return nil, fmt.Errorf("failed to add scheme: %w", err)
-  }
-
-  kubeClient, err := client.NewWithWatch(config, client.Options{Scheme: scheme})
-  if err != nil {
-    return nil, fmt.Errorf("unable to create client: %w", err)
-  }
-
-  mgr, err := resource.NewManagerFromClient(kubeClient, namespace)
+  mgr, err := a.getManager(contextName, namespace)
   if err != nil {
     return nil, err
   }
@@ -238,6 +218,10 @@ func (a *App) GetModules(contextName string, namespace string) ([]Module, error)
 
 // CreateProject creates a new project in the cluster
 func (a *App) CreateProject(contextName string, namespace string, name string) (*Project, error) {
+  if err := a.requireWritable(contextName, namespace, createProjects); err != nil {
+    return nil, err
+  }
+
   if name == "" {
     return nil, fmt.Errorf("project name is required")
   }
@@ -265,14 +249,9 @@ func (a *App) CreateProject(contextName string, namespace string, name string) (
   }, nil
 }
 
-// getManager creates a resource manager for the given context and namespace
+// getManager returns a pooled resource manager for the given context and namespace
 func (a *App) getManager(contextName string, namespace string) (*resource.Manager, error) {
-  config, err := loadContextConfig(contextName)
-  if err != nil {
-    return nil, err
-  }
-
-  return resource.NewManagerFromConfig(config, namespace)
+  return a.clients.manager(contextName, namespace)
 }
 
 // GetProjectDetails fetches complete project information
@@ -310,7 +289,7 @@ func (a *App) GetProjectStats(contextName string, namespace string, projectName
   }
 
   // Get nodes count
-  nodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+  nodes, err := a.getProjectNodes(contextName, namespace, projectName)
   if err != nil {
     return nil, fmt.Errorf("unable to get nodes: %w", err)
   }
@@ -385,16 +364,11 @@ func (a *App) GetFlowsWithGraphs(contextName string, namespace string, projectNa
   }
 
   // Fetch ALL project nodes once (to include shared nodes)
-  allNodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+  allNodesMap, err := a.getProjectNodesMap(contextName, namespace, projectName)
   if err != nil {
     return nil, fmt.Errorf("unable to get project nodes: %w", err)
   }
 
-  allNodesMap := make(map[string]v1alpha1.TinyNode, len(allNodes))
-  for _, node := range allNodes {
-    allNodesMap[node.Name] = node
-  }
-
   var result []Flow
   for _, flow := range flows {
     // Get flow name from annotation or use resource name
@@ -431,6 +405,10 @@ func (a *App) GetFlowsWithGraphs(contextName string, namespace string, projectNa
 
 // CreateFlow creates a new flow in a project
 func (a *App) CreateFlow(contextName string, namespace string, projectName string, flowName string) (*Flow, error) {
+  if err := a.requireWritable(contextName, namespace, createFlows); err != nil {
+    return nil, err
+  }
+
   if flowName == "" {
     return nil, fmt.Errorf("flow name is required")
   }
@@ -455,6 +433,10 @@ func (a *App) CreateFlow(contextName string, namespace string, projectName strin
 // UndeployFlow deletes a flow and all its nodes from the cluster.
 // It cleans up widgets referencing the flow's nodes before deletion.
 func (a *App) UndeployFlow(contextName string, namespace string, projectName string, flowResourceName string) error {
+  if err := a.requireWritable(contextName, namespace, deleteFlows); err != nil {
+    return err
+  }
+
   if flowResourceName == "" {
     return fmt.Errorf("flow resource name is required")
   }
@@ -480,6 +462,10 @@ func (a *App) UndeployFlow(contextName string, namespace string, projectName str
 
 // RenameFlow renames a flow
 func (a *App) RenameFlow(contextName string, namespace string, flowResourceName string, newName string) error {
+  if err := a.requireWritable(contextName, namespace, updateFlows); err != nil {
+    return err
+  }
+
   if flowResourceName == "" {
     return fmt.Errorf("flow resource name is required")
   }
@@ -501,22 +487,12 @@ func (a *App) RenameFlow(contextName string, namespace string, flowResourceName
 
 // GetFlowGraph fetches the graph data for a flow (for preview)
 func (a *App) GetFlowGraph(contextName string, namespace string, projectName string, flowResourceName string) (map[string]interface{}, error) {
-  mgr, err := a.getManager(contextName, namespace)
-  if err != nil {
-    return nil, err
-  }
-
   // Fetch ALL project nodes to include shared nodes from other flows
-  allNodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+  allNodesMap, err := a.getProjectNodesMap(contextName, namespace, projectName)
   if err != nil {
     return nil, fmt.Errorf("unable to get project nodes: %w", err)
   }
 
-  allNodesMap := make(map[string]v1alpha1.TinyNode, len(allNodes))
-  for _, node := range allNodes {
-    allNodesMap[node.Name] = node
-  }
-
   // Use NodesToGraphWithOptions to filter nodes for this flow (including shared nodes)
   // Pass minimal=true for preview (less data)
   nodeElements, edgeElements, err := utils.NodesToGraphWithOptions(allNodesMap, &flowResourceName, true)
@@ -574,7 +550,7 @@ func (a *App) GetWidgets(contextName string, namespace string, projectName strin
   }
 
   // Get all nodes with dashboard label
-  nodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+  nodes, err := a.getProjectNodes(contextName, namespace, projectName)
   if err != nil {
     return nil, fmt.Errorf("unable to get nodes: %w", err)
   }
@@ -793,6 +769,10 @@ func (a *App) GetWidgets(contextName string, namespace string, projectName strin
 
 // DeleteProject deletes a project and all its resources
 func (a *App) DeleteProject(contextName string, namespace string, projectName string) error {
+  if err := a.requireWritable(contextName, namespace, deleteProjects, deleteFlows, deleteNodes, deletePages); err != nil {
+    return err
+  }
+
   a.logger.Info("deleting project", "context", contextName, "namespace", namespace, "project", projectName)
 
   mgr, err := a.getManager(contextName, namespace)
@@ -844,6 +824,10 @@ func (a *App) DeleteProject(contextName string, namespace string, projectName st
 
 // RenameProject renames a project by updating its name annotation
 func (a *App) RenameProject(contextName string, namespace string, projectName string, newName string) error {
+  if err := a.requireWritable(contextName, namespace, updateProjects); err != nil {
+    return err
+  }
+
   if newName == "" {
     return fmt.Errorf("new name is required")
   }
@@ -858,6 +842,10 @@ func (a *App) RenameProject(contextName string, namespace string, projectName st
 
 // SaveProjectDescription saves a project's description to the CRD
 func (a *App) SaveProjectDescription(contextName string, namespace string, projectName string, description string) error {
+  if err := a.requireWritable(contextName, namespace, updateProjects); err != nil {
+    return err
+  }
+
   mgr, err := a.getManager(contextName, namespace)
   if err != nil {
     return err
@@ -868,6 +856,10 @@ func (a *App) SaveProjectDescription(contextName string, namespace string, proje
 
 // CreateDashboardPage creates a new dashboard page for a project
 func (a *App) CreateDashboardPage(contextName string, namespace string, projectName string, title string) (*WidgetPage, error) {
+  if err := a.requireWritable(contextName, namespace, createPages); err != nil {
+    return nil, err
+  }
+
   if title == "" {
     return nil, fmt.Errorf("page title is required")
   }
@@ -899,6 +891,10 @@ func (a *App) CreateDashboardPage(contextName string, namespace string, projectN
 
 // DeleteDashboardPage deletes a dashboard page from a project
 func (a *App) DeleteDashboardPage(contextName string, namespace string, pageResourceName string) error {
+  if err := a.requireWritable(contextName, namespace, deletePages); err != nil {
+    return err
+  }
+
   mgr, err := a.getManager(contextName, namespace)
   if err != nil {
     return err
@@ -917,6 +913,10 @@ func (a *App) DeleteDashboardPage(contextName string, namespace string, pageReso
 
 // SaveWidgets saves widget grid positions and page assignments
 func (a *App) SaveWidgets(contextName string, namespace string, projectName string, pageResourceName string, widgets []Widget) error {
+  if err := a.requireWritable(contextName, namespace, createPages, updatePages); err != nil {
+    return err
+  }
+
   mgr, err := a.getManager(contextName, namespace)
   if err != nil {
     return err
@@ -1048,105 +1048,57 @@ func findPageByName(pages []*v1alpha1.TinyWidgetPage, name string) *v1alpha1.Tin
 
 // SendSignal sends a signal to a node's control port
 func (a *App) SendSignal(contextName string, namespace string, nodeName string, port string, data string) error {
-  mgr, err := a.getManager(contextName, namespace)
-  if err != nil {
+  if err := a.requireWritable(contextName, namespace, createSignals); err != nil {
     return err
   }
 
-  return mgr.CreateSignal(a.ctx, nodeName, namespace, port, []byte(data))
-}
-
-// WatchProjectNodes starts watching nodes for real-time updates
-func (a *App) WatchProjectNodes(contextName string, namespace string, projectName string) error {
-  a.watchMu.Lock()
-  defer a.watchMu.Unlock()
-
-  // Stop any existing watcher
-  if a.watchCancel != nil {
-    a.watchCancel()
-  }
-
   mgr, err := a.getManager(contextName, namespace)
   if err != nil {
     return err
   }
 
-  watchCtx, cancel := context.WithCancel(a.ctx)
-  a.watchCancel = cancel
+  return mgr.CreateSignal(a.ctx, nodeName, namespace, port, []byte(data))
+}
 
-  watcher, err := mgr.WatchNodes(watchCtx, projectName)
-  if err != nil {
-    return fmt.Errorf("unable to start watch: %w", err)
+// WatchProjectNodes subscribes to dashboard node updates of a project. Updates are
+// emitted on the returned subscription's event name.
+func (a *App) WatchProjectNodes(contextName string, namespace string, projectName string) (*WatchSubscription, error) {
+  // Make sure the project is reachable before handing out a subscription
+  if _, err := a.getNodeCache(contextName, namespace, projectName); err != nil {
+    return nil, fmt.Errorf("unable to start watch: %w", err)
   }
 
-  // Process events in goroutine with automatic reconnection
-  go func() {
-    currentWatcher := watcher
-    defer func() {
-      if currentWatcher != nil {
-        currentWatcher.Stop()
-      }
-    }()
-
-    for {
-      select {
-      case <-watchCtx.Done():
-        return
-      case event, ok := <-currentWatcher.ResultChan():
-        if !ok {
-          // Channel closed - try to reconnect
-          currentWatcher.Stop()
-
-          select {
-          case <-watchCtx.Done():
-            return
-          case <-time.After(time.Second):
-          }
-
-          newWatcher, err := mgr.WatchNodes(watchCtx, projectName)
-          if err != nil {
-            return
-          }
-          currentWatcher = newWatcher
-          continue
-        }
-
-        node, ok := event.Object.(*v1alpha1.TinyNode)
-        if !ok {
-          continue
-        }
-
-        // Only process dashboard nodes
-        if node.Labels[v1alpha1.DashboardLabel] != "true" {
-          continue
-        }
+  key := watchKey{
+    kind:      watchKindProject,
+    context:   contextName,
+    namespace: namespace,
+    project:   projectName,
+  }
 
-        update := NodeUpdate{
-          EventType: string(event.Type),
-          NodeName:  node.Name,
-        }
+  sub := a.watches.subscribe(key, "nodeUpdate", func(_ context.Context, eventType watch.EventType, node *v1alpha1.TinyNode, _ func() map[string]v1alpha1.TinyNode) []interface{} {
+    // Only process dashboard nodes
+    if node.Labels[v1alpha1.DashboardLabel] != "true" {
+      return nil
+    }
 
-        if event.Type != watch.Deleted {
-          update.Widget = parseNodeToWidget(node)
-        }
+    update := NodeUpdate{
+      EventType: string(eventType),
+      NodeName:  node.Name,
+    }
 
-        wailsruntime.EventsEmit(a.ctx, "nodeUpdate", update)
-      }
+    if eventType != watch.Deleted {
+      update.Widget = parseNodeToWidget(node)
     }
-  }()
 
-  return nil
-}
+    return []interface{}{update}
+  }, nil)
 
-// StopWatchProjectNodes stops the node watcher
-func (a *App) StopWatchProjectNodes() error {
-  a.watchMu.Lock()
-  defer a.watchMu.Unlock()
+  return &sub, nil
+}
 
-  if a.watchCancel != nil {
-    a.watchCancel()
-    a.watchCancel = nil
-  }
+// StopWatchProjectNodes ends a project node subscription
+func (a *App) StopWatchProjectNodes(subscriptionID string) error {
+  a.watches.unsubscribe(subscriptionID)
   return nil
 }
 
@@ -1154,21 +1106,41 @@ func (a *App) StopWatchProjectNodes() error {
 
 // ExportProject exports a project to JSON format
 func (a *App) ExportProject(contextName string, namespace string, projectName string) (string, error) {
-	mgr, err := a.getManager(contextName, namespace)
+	return a.exportProject(contextName, namespace, projectName, nil)
+}
+
+// exportProject exports the whole project, or only the selection if one is given.
+func (a *App) exportProject(contextName string, namespace string, projectName string, selection *ExportSelection) (string, error) {
+	export, err := a.buildProjectExport(contextName, namespace, projectName, selection)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
 	if err != nil {
 		return "", err
 	}
 
+	return string(data), nil
+}
+
+// buildProjectExport collects flows, nodes, pages and scenarios of a project into an export.
+func (a *App) buildProjectExport(contextName string, namespace string, projectName string, selection *ExportSelection) (*utils.ProjectExport, error) {
+	mgr, err := a.getManager(contextName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all flows
 	flows, err := mgr.GetFlowList(a.ctx, projectName)
 	if err != nil {
-		return "", fmt.Errorf("unable to get flows: %w", err)
+		return nil, fmt.Errorf("unable to get flows: %w", err)
 	}
 
 	// Get all nodes
 	allNodes, err := mgr.GetProjectNodes(a.ctx, projectName)
 	if err != nil {
-		return "", fmt.Errorf("unable to get nodes: %w", err)
+		return nil, fmt.Errorf("unable to get nodes: %w", err)
 	}
 
 	allNodesMap := make(map[string]v1alpha1.TinyNode, len(allNodes))
@@ -1176,6 +1148,19 @@ func (a *App) ExportProject(contextName string, namespace string, projectName st
 		allNodesMap[node.Name] = node
 	}
 
+	// Get dashboard pages
+	pages, err := mgr.GetProjectPageWidgets(a.ctx, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get widget pages: %w", err)
+	}
+
+	if selection != nil {
+		flows, allNodesMap, pages, err = selectExport(*selection, flows, allNodesMap, pages)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Build export flows
 	var exportFlows []utils.ExportFlow
 	for _, flow := range flows {
@@ -1189,42 +1174,13 @@ func (a *App) ExportProject(contextName string, namespace string, projectName st
 		})
 	}
 
-	// Convert nodes to elements
-	nodeElements, edgeElements, err := utils.NodesToGraphWithOptions(allNodesMap, nil, false)
+	elements, err := exportElements(allNodesMap)
 	if err != nil {
-		return "", fmt.Errorf("failed to convert nodes to graph: %w", err)
-	}
-
-	// Combine elements and add flow field
-	elements := make([]map[string]interface{}, 0, len(nodeElements)+len(edgeElements))
-
-	for _, elem := range nodeElements {
-		if m, ok := elem.(map[string]interface{}); ok {
-			nodeID, _ := m["id"].(string)
-			if node, exists := allNodesMap[nodeID]; exists {
-				m["flow"] = node.Labels[v1alpha1.FlowNameLabel]
-			}
-			// Strip _control port configuration — it contains runtime state
-			// that may include sensitive data (tokens, secrets from user input)
-			stripControlPortConfig(m)
-			elements = append(elements, m)
-		}
-	}
-
-	for _, elem := range edgeElements {
-		if m, ok := elem.(map[string]interface{}); ok {
-			sourceID, _ := m["source"].(string)
-			if node, exists := allNodesMap[sourceID]; exists {
-				m["flow"] = node.Labels[v1alpha1.FlowNameLabel]
-			}
-			elements = append(elements, m)
-		}
+		return nil, err
 	}
 
-	// Get dashboard pages
-	pages, err := mgr.GetProjectPageWidgets(a.ctx, projectName)
-	if err != nil {
-		return "", fmt.Errorf("unable to get widget pages: %w", err)
+	if selection != nil {
+		elements = filterExportElements(elements, allNodesMap)
 	}
 
 	// Build export pages
@@ -1282,6 +1238,10 @@ func (a *App) ExportProject(contextName string, namespace string, projectName st
 		}
 	}
 
+	if selection != nil {
+		exportScenarios = filterExportScenarios(exportScenarios, allNodesMap)
+	}
+
 	// Get project description from CRD
 	var projectDescription string
 	project, err := mgr.GetProject(a.ctx, projectName, namespace)
@@ -1302,779 +1262,50 @@ func (a *App) ExportProject(contextName string, namespace string, projectName st
 	// Strip runtime-internal schema fields before export
 	utils.StripSchemaInternalFields(&export)
 
-	data, err := json.MarshalIndent(export, "", "  ")
-	if err != nil {
-		return "", err
-	}
-
-	return string(data), nil
-}
-
-// ImportProject imports JSON data into an existing project
-func (a *App) ImportProject(contextName string, namespace string, projectName string, jsonData string) error {
-	// Create a dedicated context with longer timeout for import operations
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-
-	emitProgress := func(msg string) {
-		wailsruntime.EventsEmit(a.ctx, "import:progress", msg)
-	}
-
-	emitProgress("Validating import data...")
-
-	// Parse import data
-	var importData utils.ProjectExport
-	if err := json.Unmarshal([]byte(jsonData), &importData); err != nil {
-		return fmt.Errorf("invalid import data: %v", err)
-	}
-
-	if importData.Version != utils.CurrentExportVersion {
-		return fmt.Errorf("unsupported import version: %d", importData.Version)
-	}
-
-	// Strip runtime-internal schema fields that may have been left in the JSON
-	utils.StripSchemaInternalFields(&importData)
-
-	// Validate import data strictly — block import if errors found
-	validationErrors, validationWarnings := utils.ValidateProjectImport(&importData)
-	for _, w := range validationWarnings {
-		a.logger.Info("import warning: " + w)
-	}
-	if len(validationErrors) > 0 {
-		return fmt.Errorf("import validation failed (%d errors):\n%s", len(validationErrors), strings.Join(validationErrors, "\n"))
-	}
-
-	emitProgress("Connecting to cluster...")
-
-	mgr, err := a.getManager(contextName, namespace)
-	if err != nil {
-		return err
-	}
-
-	// Save project description from import if present
-	if importData.Description != "" {
-		if descErr := mgr.UpdateProjectDescription(ctx, projectName, namespace, importData.Description); descErr != nil {
-			a.logger.Error(descErr, "failed to save project description from import")
-		}
-	}
-
-	// Get existing flows
-	existingFlows, err := mgr.GetFlowList(ctx, projectName)
-	if err != nil {
-		return fmt.Errorf("unable to get existing flows: %w", err)
-	}
-
-	existingFlowNames := make(map[string]bool)
-	for _, flow := range existingFlows {
-		existingFlowNames[flow.Name] = true
-	}
-
-	// Get existing nodes
-	existingNodes, err := mgr.GetProjectNodes(ctx, projectName)
-	if err != nil {
-		return fmt.Errorf("unable to get existing nodes: %w", err)
-	}
-
-	existingNodesMap := make(map[string]v1alpha1.TinyNode)
-	for _, node := range existingNodes {
-		existingNodesMap[node.Name] = node
-	}
-
-	emitProgress(fmt.Sprintf("Creating flows... (%d flows)", len(importData.TinyFlows)))
-
-	// Create flows that don't exist
-	flowResourceNameMap := make(map[string]string) // old name -> new name
-	for _, importFlow := range importData.TinyFlows {
-		a.logger.Info("processing flow", "oldResourceName", importFlow.ResourceName, "displayName", importFlow.Name)
-		if existingFlowNames[importFlow.ResourceName] {
-			flowResourceNameMap[importFlow.ResourceName] = importFlow.ResourceName
-			a.logger.Info("flow already exists", "resourceName", importFlow.ResourceName)
-			continue
+	// A partial export must still be importable
+	if selection != nil {
+		if validationErrors, _ := utils.ValidateProjectImport(&export); len(validationErrors) > 0 {
+			return nil, fmt.Errorf("selected export is not importable (%d errors):\n%s", len(validationErrors), strings.Join(validationErrors, "\n"))
 		}
-		newResourceName, err := mgr.CreateFlow(ctx, namespace, projectName, importFlow.Name)
-		if err != nil {
-			a.logger.Error(err, "failed to create flow", "name", importFlow.Name)
-			continue
-		}
-		flowResourceNameMap[importFlow.ResourceName] = *newResourceName
-		a.logger.Info("created new flow", "oldResourceName", importFlow.ResourceName, "newResourceName", *newResourceName)
 	}
-	a.logger.Info("flow mapping complete", "mappings", flowResourceNameMap)
-
-	// Track errors for reporting
-	var failedNodes []string
-	var importedNodes int
-
-	// Map old node IDs to new node names (for edge translation)
-	nodeIDMap := make(map[string]string) // oldID -> newName
-
-	emitProgress(fmt.Sprintf("Importing nodes... (%d elements)", len(importData.Elements)))
-
-	// Import nodes
-	for _, elem := range importData.Elements {
-		elemType, _ := elem["type"].(string)
-		if elemType == "edge" || elemType == "tinyEdge" || elemType == "" {
-			continue
-		}
-
-		oldNodeID, _ := elem["id"].(string)
-		if oldNodeID == "" {
-			continue
-		}
 
-		// Check if node already exists — update it instead of skipping
-		if existing, exists := existingNodesMap[oldNodeID]; exists {
-			nodeIDMap[oldNodeID] = oldNodeID
-			a.updateExistingNode(ctx, &existing, elem, mgr, flowResourceNameMap)
-			continue
-		}
-
-		// Get flow for this node
-		flowName, _ := elem["flow"].(string)
-		if flowName == "" {
-			a.logger.Info("skipping node - no flow name", "nodeID", oldNodeID)
-			continue
-		}
-		newFlowName := flowResourceNameMap[flowName]
-		if newFlowName == "" {
-			a.logger.Info("skipping node - flow not in map", "nodeID", oldNodeID, "flowName", flowName, "availableFlows", flowResourceNameMap)
-			continue
-		}
-		a.logger.Info("creating node", "nodeID", oldNodeID, "oldFlow", flowName, "newFlow", newFlowName)
-
-		data, _ := elem["data"].(map[string]interface{})
-		if data == nil {
-			continue
-		}
-
-		component, _ := data["component"].(string)
-		module, _ := data["module"].(string)
-		if component == "" || module == "" {
-			continue
-		}
-
-		// Get position
-		position, _ := elem["position"].(map[string]interface{})
-		posX, posY := 0, 0
-		if position != nil {
-			if x, ok := position["x"].(float64); ok {
-				posX = int(x)
-			}
-			if y, ok := position["y"].(float64); ok {
-				posY = int(y)
-			}
-		}
-
-		// Create node with proper naming: {hash}.{module}.{component}-{suffix}
-		// Use suffix from original node ID if available to preserve uniqueness
-		// when multiple nodes share the same component type (e.g., two Tickers)
-		nodeGenerateName := utils.GetNodeGenerateName(projectName, newFlowName, module, component)
-		suffix := strconv.FormatInt(time.Now().UnixNano(), 36)[:5]
-		if oldNodeID != "" {
-			if idx := strings.LastIndex(oldNodeID, "-"); idx >= 0 && idx < len(oldNodeID)-1 {
-				suffix = oldNodeID[idx+1:]
-			}
-		}
-		nodeName := nodeGenerateName + suffix
-
-		// Get label from data
-		label, _ := data["label"].(string)
-		if label == "" {
-			label = component
-		}
-
-		// Get spin value
-		spin := 0
-		if spinVal, ok := data["spin"].(float64); ok {
-			spin = int(spinVal)
-		}
-
-		// Get dashboard flag
-		dashboard, _ := data["dashboard"].(string)
-
-		labels := map[string]string{
-			v1alpha1.FlowNameLabel:    newFlowName,
-			v1alpha1.ProjectNameLabel: projectName,
-		}
-		if dashboard == "true" {
-			labels[v1alpha1.DashboardLabel] = "true"
-		}
-
-		// Extract port configurations from handles
-		var ports []v1alpha1.TinyNodePortConfig
-		if handles, ok := data["handles"].([]interface{}); ok {
-			a.logger.Info("processing handles for node", "component", component, "handleCount", len(handles))
-			for _, h := range handles {
-				handle, ok := h.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				portID, _ := handle["id"].(string)
-				if portID == "" {
-					continue
-				}
-
-				// Skip source ports — they are runtime-generated and their bare
-				// configurable definitions would overwrite richer definitions from
-				// target ports (like _settings) in GetConfigurableDefinitions()
-				if portType, _ := handle["type"].(string); portType == "source" {
-					continue
-				}
-
-				// Get configuration and schema from handle
-				config := handle["configuration"]
-				schema := handle["schema"]
-
-				// Marshal config to JSON for storage
-				var configBytes []byte
-				if config != nil {
-					var err error
-					configBytes, err = json.Marshal(config)
-					if err != nil {
-						a.logger.Error(err, "failed to marshal port config", "port", portID)
-						continue
-					}
-					// Check if empty
-					if string(configBytes) == "{}" || string(configBytes) == "null" {
-						configBytes = nil
-					}
-				}
-
-				// Marshal schema to JSON for storage
-				var schemaBytes []byte
-				if schema != nil {
-					var err error
-					schemaBytes, err = json.Marshal(schema)
-					if err != nil {
-						a.logger.Error(err, "failed to marshal port schema", "port", portID)
-					}
-				}
-
-				// Skip if both config and schema are empty
-				if len(configBytes) == 0 && len(schemaBytes) == 0 {
-					continue
-				}
-
-				ports = append(ports, v1alpha1.TinyNodePortConfig{
-					Port:          portID,
-					Configuration: configBytes,
-					Schema:        schemaBytes,
-				})
-				a.logger.Info("added port config", "port", portID, "configLen", len(configBytes), "schemaLen", len(schemaBytes))
-			}
-		} else {
-			a.logger.Info("no handles found for node", "component", component, "dataKeys", getMapKeys(data))
-		}
-
-		annotations := map[string]string{
-			v1alpha1.ComponentPosXAnnotation:    strconv.Itoa(posX),
-			v1alpha1.ComponentPosYAnnotation:    strconv.Itoa(posY),
-			v1alpha1.ComponentPosSpinAnnotation: strconv.Itoa(spin),
-			v1alpha1.NodeLabelAnnotation:        label,
-		}
-
-		if sharedWith, _ := data["shared_with_flows"].(string); sharedWith != "" {
-			annotations[v1alpha1.SharedWithFlowsAnnotation] = utils.ResolveSharedFlows(sharedWith, flowResourceNameMap)
-		}
-
-		node := &v1alpha1.TinyNode{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:        nodeName,
-				Namespace:   namespace,
-				Labels:      labels,
-				Annotations: annotations,
-			},
-			Spec: v1alpha1.TinyNodeSpec{
-				Module:    module,
-				Component: component,
-				Ports:     ports,
-			},
-		}
-
-		// Use async CreateNode - don't wait for sync (too slow)
-		if err := mgr.CreateNode(ctx, node); err != nil {
-			a.logger.Error(err, "failed to create node", "component", component)
-			failedNodes = append(failedNodes, component)
-		} else {
-			a.logger.Info("imported node", "component", component, "name", nodeName, "portsCount", len(ports))
-			nodeIDMap[oldNodeID] = nodeName
-			importedNodes++
-		}
-	}
-
-	// Wait for nodes to be created before adding edges
-	if len(nodeIDMap) > 0 {
-		emitProgress(fmt.Sprintf("Waiting for %d nodes to be ready...", len(nodeIDMap)))
-		a.logger.Info("waiting for nodes to be ready before adding edges", "nodeCount", len(nodeIDMap))
-		time.Sleep(3 * time.Second)
-	}
-
-	emitProgress("Processing edges...")
-
-	// Import edges - collect all updates per node to do a single update
-	// This prevents race conditions where controller reconciliation between updates could reset data
-	edgesBySourceNode := make(map[string][]v1alpha1.TinyNodeEdge)
-	portConfigsByTargetNode := make(map[string][]v1alpha1.TinyNodePortConfig)
-
-	for _, elem := range importData.Elements {
-		elemType, _ := elem["type"].(string)
-		if elemType != "edge" && elemType != "tinyEdge" {
-			continue
-		}
-
-		oldSourceID, _ := elem["source"].(string)
-		sourceHandle, _ := elem["sourceHandle"].(string)
-		oldTargetID, _ := elem["target"].(string)
-		targetHandle, _ := elem["targetHandle"].(string)
-		flowName, _ := elem["flow"].(string)
-
-		// Log the raw edge data for debugging
-		edgeDataRaw, _ := json.Marshal(elem["data"])
-		a.logger.Info("processing edge", "source", oldSourceID, "sourceHandle", sourceHandle, "target", oldTargetID, "targetHandle", targetHandle, "dataRaw", string(edgeDataRaw))
-
-		// Translate old IDs to new names
-		newSourceName := nodeIDMap[oldSourceID]
-		newTargetName := nodeIDMap[oldTargetID]
-		newFlowName := flowResourceNameMap[flowName]
-
-		if newSourceName == "" || newTargetName == "" || newFlowName == "" {
-			a.logger.Info("skipping edge - missing node mapping", "source", oldSourceID, "target", oldTargetID)
-			continue
-		}
-
-		// Generate new edge ID with new node names: {source}_{sourcePort}-{target}_{targetPort}
-		newEdgeID := fmt.Sprintf("%s_%s-%s_%s", newSourceName, sourceHandle, newTargetName, targetHandle)
-
-		edge := v1alpha1.TinyNodeEdge{
-			ID:     newEdgeID,
-			Port:   sourceHandle,
-			To:     newTargetName + ":" + targetHandle,
-			FlowID: newFlowName,
-		}
-		edgesBySourceNode[newSourceName] = append(edgesBySourceNode[newSourceName], edge)
-
-		// Extract edge configuration from elem["data"]["configuration"]
-		// This needs to be added as a port config on the TARGET node
-		edgeData, hasData := elem["data"].(map[string]interface{})
-		if !hasData {
-			a.logger.Info("edge has no data map", "edge", newEdgeID)
-			continue
-		}
-
-		var configBytes []byte
-		var schemaBytes []byte
-
-		config := edgeData["configuration"]
-		a.logger.Info("edge configuration check", "edge", newEdgeID, "hasConfig", config != nil, "configType", fmt.Sprintf("%T", config))
-
-		if config != nil {
-			var err error
-			configBytes, err = json.Marshal(config)
-			if err != nil {
-				a.logger.Error(err, "failed to marshal edge config", "edge", newEdgeID)
-			} else {
-				a.logger.Info("edge config marshaled", "edge", newEdgeID, "configLen", len(configBytes), "configPreview", truncateString(string(configBytes), 200))
-			}
-		}
-
-		if edgeSchema := edgeData["schema"]; edgeSchema != nil {
-			var err error
-			schemaBytes, err = json.Marshal(edgeSchema)
-			if err != nil {
-				a.logger.Error(err, "failed to marshal edge schema", "edge", newEdgeID)
-			}
-		}
-
-		if len(configBytes) > 0 {
-			sourcePortFullName := newSourceName + ":" + sourceHandle
-			portConfig := v1alpha1.TinyNodePortConfig{
-				From:          sourcePortFullName,
-				Port:          targetHandle,
-				Configuration: configBytes,
-				Schema:        schemaBytes,
-				FlowID:        newFlowName,
-			}
-			portConfigsByTargetNode[newTargetName] = append(portConfigsByTargetNode[newTargetName], portConfig)
-			a.logger.Info("prepared edge port config", "target", newTargetName, "port", targetHandle, "from", sourcePortFullName, "configLen", len(configBytes), "schemaLen", len(schemaBytes))
-		}
-	}
-
-	// Collect all nodes that need updates
-	allNodesToUpdate := make(map[string]bool)
-	for nodeName := range edgesBySourceNode {
-		allNodesToUpdate[nodeName] = true
-	}
-	for nodeName := range portConfigsByTargetNode {
-		allNodesToUpdate[nodeName] = true
-	}
-
-	// Build set of imported flow resource names for cleanup filtering
-	importedFlowNames := make(map[string]bool)
-	for _, name := range flowResourceNameMap {
-		importedFlowNames[name] = true
-	}
-
-	a.logger.Info("edge port configs summary",
-		"totalEdges", len(edgesBySourceNode),
-		"totalPortConfigs", len(portConfigsByTargetNode),
-		"nodesToUpdate", len(allNodesToUpdate))
-
-	emitProgress(fmt.Sprintf("Updating %d nodes with edge configurations...", len(allNodesToUpdate)))
-
-	// Update each node ONCE with all its changes (both edges and port configs)
-	// This prevents race conditions with controller reconciliation
-	for nodeName := range allNodesToUpdate {
-		node, err := mgr.GetNode(ctx, nodeName, namespace)
-		if err != nil {
-			a.logger.Error(err, "failed to get node for update", "node", nodeName)
-			continue
-		}
-
-		existingPortsCount := len(node.Spec.Ports)
-		existingEdgesCount := len(node.Spec.Edges)
-
-		// Remove old edges belonging to imported flows, then append new ones.
-		// This prevents stale edges from accumulating when node IDs change across re-imports.
-		if edges, ok := edgesBySourceNode[nodeName]; ok {
-			node.Spec.Edges = utils.ReplaceFlowEdges(node.Spec.Edges, importedFlowNames, edges)
-			a.logger.Info("replaced edges on node", "node", nodeName, "importedEdgeCount", len(edges))
-		}
-
-		// Remove old edge port configs belonging to imported flows, then append new ones.
-		if portConfigs, ok := portConfigsByTargetNode[nodeName]; ok {
-			node.Spec.Ports = utils.ReplaceFlowPortConfigs(node.Spec.Ports, importedFlowNames, portConfigs)
-			a.logger.Info("replaced port configs on node", "node", nodeName, "importedPortConfigCount", len(portConfigs))
-		}
-
-		if err := mgr.UpdateNode(ctx, node); err != nil {
-			a.logger.Error(err, "failed to update node", "node", nodeName)
-		} else {
-			a.logger.Info("updated node successfully", "node", nodeName,
-				"existingEdges", existingEdgesCount, "totalEdges", len(node.Spec.Edges),
-				"existingPorts", existingPortsCount, "totalPorts", len(node.Spec.Ports))
-		}
-	}
-
-	// Verification: Read back nodes and check if port configs were persisted
-	a.logger.Info("verifying port configs were persisted...")
-	time.Sleep(1 * time.Second) // Small delay to let k8s process
-	for nodeName := range portConfigsByTargetNode {
-		verifyNode, err := mgr.GetNode(ctx, nodeName, namespace)
-		if err != nil {
-			a.logger.Error(err, "failed to verify node", "node", nodeName)
-			continue
-		}
-		edgePortConfigCount := 0
-		for _, pc := range verifyNode.Spec.Ports {
-			if pc.From != "" {
-				edgePortConfigCount++
-				a.logger.Info("verified edge port config",
-					"node", nodeName,
-					"from", pc.From,
-					"port", pc.Port,
-					"configLen", len(pc.Configuration))
-			}
-		}
-		a.logger.Info("verification result", "node", nodeName,
-			"totalPorts", len(verifyNode.Spec.Ports),
-			"edgePortConfigs", edgePortConfigCount)
-	}
-
-	if len(importData.Pages) > 0 {
-		emitProgress(fmt.Sprintf("Creating dashboard pages... (%d pages)", len(importData.Pages)))
-	}
+	return &export, nil
+}
 
-	// Import pages with widgets
-	var failedPages []string
-	existingPages, err := mgr.GetProjectPageWidgets(ctx, projectName)
+// exportElements converts nodes to export elements, each with the flow it belongs to.
+func exportElements(allNodesMap map[string]v1alpha1.TinyNode) ([]map[string]interface{}, error) {
+	nodeElements, edgeElements, err := utils.NodesToGraphWithOptions(allNodesMap, nil, false)
 	if err != nil {
-		return fmt.Errorf("unable to get existing pages: %w", err)
-	}
-	a.logger.Info("existing pages before import", "count", len(existingPages), "projectName", projectName)
-
-	// Build map of existing page titles to avoid duplicates
-	existingPageTitles := make(map[string]bool)
-	for _, page := range existingPages {
-		title := page.Annotations[v1alpha1.PageTitleAnnotation]
-		if title == "" {
-			title = page.Name
-		}
-		existingPageTitles[title] = true
-	}
-
-	for _, importPage := range importData.Pages {
-		// Check by title, not by resource name (resource names are regenerated)
-		if existingPageTitles[importPage.Title] {
-			a.logger.Info("skipping page - already exists", "title", importPage.Title)
-			continue
-		}
-
-		// Build widgets with translated port references first
-		var widgets []v1alpha1.TinyWidget
-		for _, importWidget := range importPage.Widgets {
-			// Translate port reference: "oldNodeID:portName" -> "newNodeName:portName"
-			portParts := strings.SplitN(importWidget.Port, ":", 2)
-			if len(portParts) != 2 {
-				a.logger.Info("skipping widget - invalid port format", "port", importWidget.Port)
-				continue
-			}
-			oldNodeID := portParts[0]
-			portName := portParts[1]
-
-			newNodeName := nodeIDMap[oldNodeID]
-			if newNodeName == "" {
-				a.logger.Info("skipping widget - node not found in map", "oldNodeID", oldNodeID, "availableNodes", len(nodeIDMap))
-				continue
-			}
-
-			newPort := newNodeName + ":" + portName
-			widget := v1alpha1.TinyWidget{
-				Port:  newPort,
-				Name:  importWidget.Name,
-				GridX: importWidget.GridX,
-				GridY: importWidget.GridY,
-				GridW: importWidget.GridW,
-				GridH: importWidget.GridH,
-			}
-			if len(importWidget.SchemaPatch) > 0 {
-				widget.SchemaPatch = []byte(importWidget.SchemaPatch)
-			}
-			widgets = append(widgets, widget)
-			a.logger.Info("translated widget port", "oldPort", importWidget.Port, "newPort", newPort, "widgetName", importWidget.Name)
-		}
-
-		// Create the page
-		a.logger.Info("creating page", "title", importPage.Title, "project", projectName, "namespace", namespace, "sortIdx", importPage.SortIdx)
-		newPageName, err := mgr.CreatePage(ctx, importPage.Title, projectName, namespace, importPage.SortIdx)
-		if err != nil {
-			a.logger.Error(err, "failed to create page", "title", importPage.Title)
-			failedPages = append(failedPages, importPage.Title)
-			continue
-		}
-		if newPageName == nil {
-			a.logger.Error(nil, "CreatePage returned nil name without error", "title", importPage.Title)
-			failedPages = append(failedPages, importPage.Title)
-			continue
-		}
-		a.logger.Info("page created successfully", "title", importPage.Title, "resourceName", *newPageName)
-
-		// Update page with widgets if any
-		if len(widgets) > 0 {
-			// Small delay to ensure page is queryable
-			time.Sleep(500 * time.Millisecond)
-
-			// Fetch the page we just created to update it
-			allPages, err := mgr.GetProjectPageWidgets(ctx, projectName)
-			if err != nil {
-				a.logger.Error(err, "failed to get pages after creation", "project", projectName)
-				continue
-			}
-			a.logger.Info("fetched pages after creation", "count", len(allPages))
-
-			found := false
-			for i := range allPages {
-				if allPages[i].Name == *newPageName {
-					found = true
-					allPages[i].Spec.Widgets = widgets
-					if err := mgr.UpdatePage(ctx, &allPages[i]); err != nil {
-						a.logger.Error(err, "failed to update page with widgets", "page", *newPageName)
-					} else {
-						a.logger.Info("added widgets to page", "page", *newPageName, "widgetCount", len(widgets))
-					}
-					break
-				}
-			}
-			if !found {
-				a.logger.Error(nil, "created page not found when trying to add widgets", "expectedName", *newPageName, "availablePages", len(allPages))
-			}
-		} else {
-			a.logger.Info("no widgets to add to page", "page", *newPageName)
-		}
+		return nil, fmt.Errorf("failed to convert nodes to graph: %w", err)
 	}
 
-	// Final verification
-	finalPages, _ := mgr.GetProjectPageWidgets(ctx, projectName)
-	a.logger.Info("pages after import complete", "count", len(finalPages), "projectName", projectName)
-
-	// Import scenarios (skip duplicates by name)
-	if len(importData.Scenarios) > 0 {
-		emitProgress(fmt.Sprintf("Creating scenarios... (%d scenarios)", len(importData.Scenarios)))
-
-		existingScenarioNames := make(map[string]bool)
-		if existing, err := mgr.GetProjectScenarios(ctx, projectName); err == nil {
-			for _, s := range existing {
-				name := s.Annotations[v1alpha1.ScenarioNameAnnotation]
-				if name == "" {
-					name = s.Name
-				}
-				existingScenarioNames[name] = true
-			}
-		}
+	// Combine elements and add flow field
+	elements := make([]map[string]interface{}, 0, len(nodeElements)+len(edgeElements))
 
-		for _, importScenario := range importData.Scenarios {
-			if existingScenarioNames[importScenario.Name] {
-				a.logger.Info("skipping scenario - already exists", "name", importScenario.Name)
-				continue
-			}
-			scenario, err := mgr.CreateScenario(ctx, importScenario.Name, projectName)
-			if err != nil {
-				a.logger.Error(err, "failed to create scenario", "name", importScenario.Name)
-				continue
-			}
-			var ports []v1alpha1.ScenarioPortData
-			for _, p := range importScenario.Ports {
-				port := p.Port
-				// Remap port node references using nodeIDMap
-				parts := strings.SplitN(port, ":", 2)
-				if len(parts) == 2 {
-					if newName, ok := nodeIDMap[parts[0]]; ok {
-						port = newName + ":" + parts[1]
-					}
-				}
-				ports = append(ports, v1alpha1.ScenarioPortData{
-					Port: port,
-					Data: p.Data,
-				})
-			}
-			scenario.Spec.Ports = ports
-			if err := mgr.UpdateScenario(ctx, scenario); err != nil {
-				a.logger.Error(err, "failed to update scenario", "name", importScenario.Name)
+	for _, elem := range nodeElements {
+		if m, ok := elem.(map[string]interface{}); ok {
+			nodeID, _ := m["id"].(string)
+			if node, exists := allNodesMap[nodeID]; exists {
+				m["flow"] = node.Labels[v1alpha1.FlowNameLabel]
 			}
+			// Strip _control port configuration — it contains runtime state
+			// that may include sensitive data (tokens, secrets from user input)
+			stripControlPortConfig(m)
+			elements = append(elements, m)
 		}
 	}
 
-	// Build detailed summary
-	var summary strings.Builder
-	summary.WriteString(fmt.Sprintf("Import complete: %d nodes imported", importedNodes))
-	if len(failedNodes) > 0 {
-		summary.WriteString(fmt.Sprintf(", %d nodes failed (%v)", len(failedNodes), failedNodes))
-	}
-	summary.WriteString(fmt.Sprintf(", %d pages imported", len(finalPages)))
-	if len(failedPages) > 0 {
-		summary.WriteString(fmt.Sprintf(", %d pages failed (%v)", len(failedPages), failedPages))
-	}
-	summary.WriteString(fmt.Sprintf(", nodeIDMap has %d entries", len(nodeIDMap)))
-	a.logger.Info(summary.String())
-
-	emitProgress(fmt.Sprintf("Import complete! (%d nodes, %d edges, %d pages)", importedNodes, len(edgesBySourceNode), len(importData.Pages)))
-
-	// Return error summary if any failures
-	if len(failedNodes) > 0 || len(failedPages) > 0 {
-		return fmt.Errorf("import completed with errors: %d nodes imported, %d nodes failed (%v), %d pages failed (%v)",
-			importedNodes, len(failedNodes), failedNodes, len(failedPages), failedPages)
-	}
-
-	return nil
-}
-
-// updateExistingNode updates an existing node with imported data (position, label, module version, port configs from handles)
-func (a *App) updateExistingNode(ctx context.Context, node *v1alpha1.TinyNode, elem map[string]interface{}, mgr resource.ManagerInterface, flowResourceNameMap map[string]string) {
-	data, _ := elem["data"].(map[string]interface{})
-	if data == nil {
-		a.logger.Info("import element has no data, skipping update", "node", node.Name)
-		return
-	}
-
-	if node.Annotations == nil {
-		node.Annotations = make(map[string]string)
-	}
-
-	// Update position
-	if position, _ := elem["position"].(map[string]interface{}); position != nil {
-		if x, ok := position["x"].(float64); ok {
-			node.Annotations[v1alpha1.ComponentPosXAnnotation] = strconv.Itoa(int(x))
-		}
-		if y, ok := position["y"].(float64); ok {
-			node.Annotations[v1alpha1.ComponentPosYAnnotation] = strconv.Itoa(int(y))
-		}
-	}
-
-	// Update spin
-	if spinVal, ok := data["spin"].(float64); ok {
-		node.Annotations[v1alpha1.ComponentPosSpinAnnotation] = strconv.Itoa(int(spinVal))
-	}
-
-	// Update label
-	if label, ok := data["label"].(string); ok && label != "" {
-		node.Annotations[v1alpha1.NodeLabelAnnotation] = label
-	}
-
-	// Update shared_with_flows annotation (resolve import flow names to cluster names)
-	if sharedWith, _ := data["shared_with_flows"].(string); sharedWith != "" {
-		node.Annotations[v1alpha1.SharedWithFlowsAnnotation] = utils.ResolveSharedFlows(sharedWith, flowResourceNameMap)
-	} else {
-		delete(node.Annotations, v1alpha1.SharedWithFlowsAnnotation)
-	}
-
-	// Rebuild port configs from handles (same logic as node creation)
-	var handlePorts []v1alpha1.TinyNodePortConfig
-	if handles, ok := data["handles"].([]interface{}); ok {
-		for _, h := range handles {
-			handle, ok := h.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			portID, _ := handle["id"].(string)
-			if portID == "" {
-				continue
-			}
-
-			// Skip source ports — runtime-generated, bare configurable defs overwrite richer ones
-			if portType, _ := handle["type"].(string); portType == "source" {
-				continue
-			}
-
-			config := handle["configuration"]
-			schema := handle["schema"]
-
-			var configBytes []byte
-			if config != nil {
-				var err error
-				configBytes, err = json.Marshal(config)
-				if err != nil {
-					a.logger.Error(err, "failed to marshal port config", "port", portID)
-					continue
-				}
-				if string(configBytes) == "{}" || string(configBytes) == "null" {
-					configBytes = nil
-				}
-			}
-
-			var schemaBytes []byte
-			if schema != nil {
-				var err error
-				schemaBytes, err = json.Marshal(schema)
-				if err != nil {
-					a.logger.Error(err, "failed to marshal port schema", "port", portID)
-				}
-			}
-
-			if len(configBytes) == 0 && len(schemaBytes) == 0 {
-				continue
+	for _, elem := range edgeElements {
+		if m, ok := elem.(map[string]interface{}); ok {
+			sourceID, _ := m["source"].(string)
+			if node, exists := allNodesMap[sourceID]; exists {
+				m["flow"] = node.Labels[v1alpha1.FlowNameLabel]
 			}
-
-			handlePorts = append(handlePorts, v1alpha1.TinyNodePortConfig{
-				Port:          portID,
-				Configuration: configBytes,
-				Schema:        schemaBytes,
-			})
-		}
-	}
-
-	// Replace handle-level port configs (From=""), keep edge-level port configs (From!="")
-	var edgePorts []v1alpha1.TinyNodePortConfig
-	for _, pc := range node.Spec.Ports {
-		if pc.From != "" {
-			edgePorts = append(edgePorts, pc)
+			elements = append(elements, m)
 		}
 	}
-	node.Spec.Ports = append(handlePorts, edgePorts...)
 
-	if err := mgr.UpdateNode(ctx, node); err != nil {
-		a.logger.Error(err, "failed to update existing node", "node", node.Name)
-	} else {
-		a.logger.Info("updated existing node", "node", node.Name, "handlePorts", len(handlePorts))
-	}
+	return elements, nil
 }
 
 // getMapKeys returns the keys of a map for logging purposes
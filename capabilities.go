@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// capabilitiesTTL is how long reviewed permissions are trusted before they are reviewed again
+	capabilitiesTTL = 5 * time.Minute
+	// capabilitiesErrorTTL is how long a failed review is reported before it is retried
+	capabilitiesErrorTTL = 10 * time.Second
+)
+
+// access is a verb on a tinysystems resource a method needs.
+type access struct {
+	verb     string
+	resource string
+}
+
+func (a access) String() string {
+	return a.verb + " " + a.resource
+}
+
+// Accesses the mutating methods check up front
+var (
+	createNodes    = access{"create", "tinynodes"}
+	updateNodes    = access{"update", "tinynodes"}
+	deleteNodes    = access{"delete", "tinynodes"}
+	createFlows    = access{"create", "tinyflows"}
+	updateFlows    = access{"update", "tinyflows"}
+	deleteFlows    = access{"delete", "tinyflows"}
+	createProjects = access{"create", "tinyprojects"}
+	updateProjects = access{"update", "tinyprojects"}
+	deleteProjects = access{"delete", "tinyprojects"}
+	createPages    = access{"create", "tinywidgetpages"}
+	updatePages    = access{"update", "tinywidgetpages"}
+	deletePages    = access{"delete", "tinywidgetpages"}
+	createSignals  = access{"create", "tinysignals"}
+)
+
+// Capabilities tells what the current user may do with tinysystems resources in a namespace.
+// A session is read-only when nodes and flows can be read but nodes can't be updated, which
+// is what editing a flow needs.
+type Capabilities struct {
+	Context   string                     `json:"context"`
+	Namespace string                     `json:"namespace"`
+	ReadOnly  bool                       `json:"readOnly"`
+	Resources map[string]map[string]bool `json:"resources"` // resource -> verb -> allowed
+}
+
+// ForbiddenError is returned up front by methods that need an access the user doesn't have.
+type ForbiddenError struct {
+	Context   string
+	Namespace string
+	Denied    []string // e.g. "create tinynodes"
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("forbidden: not allowed to %s in namespace '%s' of context '%s'",
+		strings.Join(e.Denied, ", "), e.Namespace, e.Context)
+}
+
+// capabilityCache keeps reviewed capabilities per context and namespace.
+type capabilityCache struct {
+	mu      sync.Mutex
+	entries map[poolKey]*cachedCapabilities
+}
+
+// cachedCapabilities is a review, in flight until ready is closed.
+type cachedCapabilities struct {
+	ready      chan struct{}
+	caps       *Capabilities
+	err        error
+	reviewedAt time.Time
+}
+
+// fresh reports whether a finished review may still be used.
+func (e *cachedCapabilities) fresh() bool {
+	ttl := capabilitiesTTL
+	if e.err != nil {
+		ttl = capabilitiesErrorTTL
+	}
+	return time.Since(e.reviewedAt) < ttl
+}
+
+func newCapabilityCache() *capabilityCache {
+	return &capabilityCache{entries: make(map[poolKey]*cachedCapabilities)}
+}
+
+// invalidate drops all cached capabilities, e.g. when credentials change.
+func (c *capabilityCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[poolKey]*cachedCapabilities)
+}
+
+// invalidateContext drops the cached capabilities of a single context.
+func (c *capabilityCache) invalidateContext(contextName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.context == contextName {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// GetCapabilities returns what the current user may do in a namespace, so the UI can disable
+// controls of a read-only session.
+func (a *App) GetCapabilities(contextName, namespace string) (*Capabilities, error) {
+	return a.capabilitiesFor(contextName, namespace)
+}
+
+// capabilitiesFor returns cached capabilities, reviewing them when they are missing or stale.
+// Concurrent callers share one review, and a failed review is cached for a short while.
+func (a *App) capabilitiesFor(contextName, namespace string) (*Capabilities, error) {
+	key := poolKey{context: contextName, namespace: namespace}
+
+	a.capabilities.mu.Lock()
+	entry, ok := a.capabilities.entries[key]
+	if ok {
+		select {
+		case <-entry.ready:
+			ok = entry.fresh()
+		default:
+		}
+	}
+	if ok {
+		a.capabilities.mu.Unlock()
+		<-entry.ready
+		return entry.caps, entry.err
+	}
+	entry = &cachedCapabilities{ready: make(chan struct{})}
+	a.capabilities.entries[key] = entry
+	a.capabilities.mu.Unlock()
+
+	entry.caps, entry.err = a.reviewCapabilities(contextName, namespace)
+	entry.reviewedAt = time.Now()
+	close(entry.ready)
+	return entry.caps, entry.err
+}
+
+// reviewCapabilities asks the API server what the user may do in a namespace.
+func (a *App) reviewCapabilities(contextName, namespace string) (*Capabilities, error) {
+	clientset, err := a.clients.clientset(contextName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client configuration for context '%s': %w", contextName, err)
+	}
+
+	caps := &Capabilities{
+		Context:   contextName,
+		Namespace: namespace,
+		Resources: make(map[string]map[string]bool),
+	}
+	for _, rv := range reviewAccess(a.ctx, clientset, namespace) {
+		if rv.err != nil {
+			return nil, fmt.Errorf("unable to review permissions: %w", rv.err)
+		}
+		if caps.Resources[rv.resource] == nil {
+			caps.Resources[rv.resource] = make(map[string]bool)
+		}
+		caps.Resources[rv.resource][rv.verb] = rv.allowed
+	}
+	caps.ReadOnly = !caps.allowed(updateNodes) && caps.canRead("tinynodes") && caps.canRead("tinyflows")
+
+	if caps.ReadOnly {
+		a.logger.Info("session is read-only", "context", contextName, "namespace", namespace)
+	}
+	return caps, nil
+}
+
+// canRead reports whether a resource can be listed and fetched.
+func (c *Capabilities) canRead(resource string) bool {
+	return c.Resources[resource]["get"] && c.Resources[resource]["list"]
+}
+
+// allowed reports whether an access was granted.
+func (c *Capabilities) allowed(need access) bool {
+	return c.Resources[need.resource][need.verb]
+}
+
+// requireWritable returns a ForbiddenError when the user lacks any of the accesses a method
+// needs. If permissions can't be reviewed the call goes ahead and the API server has the
+// final say.
+func (a *App) requireWritable(contextName, namespace string, needs ...access) error {
+	caps, err := a.capabilitiesFor(contextName, namespace)
+	if err != nil {
+		a.logger.Error(err, "unable to check write access", "context", contextName, "namespace", namespace)
+		return nil
+	}
+	var denied []string
+	for _, need := range needs {
+		if !caps.allowed(need) {
+			denied = append(denied, need.String())
+		}
+	}
+	if len(denied) > 0 {
+		return &ForbiddenError{Context: contextName, Namespace: namespace, Denied: denied}
+	}
+	return nil
+}
+
+// accessReview is the outcome of reviewing one verb on a resource.
+type accessReview struct {
+	resource string
+	verb     string
+	allowed  bool
+	err      error
+}
+
+// reviewAccess tells which of the verbs the client uses on each tinysystems resource the
+// current user may use in a namespace. A single SelfSubjectRulesReview answers them all;
+// only when the authorizer can't list its rules are the verbs the rules don't grant
+// reviewed one by one.
+func reviewAccess(ctx context.Context, clientset *kubernetes.Clientset, namespace string) []accessReview {
+	var reviews []accessReview
+	for _, r := range preflightResources {
+		for _, verb := range r.verbs {
+			reviews = append(reviews, accessReview{resource: r.resource, verb: verb})
+		}
+	}
+
+	ssrr := &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}
+	result, err := clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, ssrr, v1.CreateOptions{})
+	if err != nil {
+		for i := range reviews {
+			reviews[i].err = err
+		}
+		return reviews
+	}
+
+	var pending []*accessReview
+	for i := range reviews {
+		reviews[i].allowed = rulesAllow(result.Status.ResourceRules, reviews[i].verb, reviews[i].resource)
+		if !reviews[i].allowed && result.Status.Incomplete {
+			pending = append(pending, &reviews[i])
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, rv := range pending {
+		wg.Add(1)
+		go func(rv *accessReview) {
+			defer wg.Done()
+			ssar := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: namespace,
+						Verb:      rv.verb,
+						Group:     v1alpha1.GroupVersion.Group,
+						Resource:  rv.resource,
+					},
+				},
+			}
+			result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, v1.CreateOptions{})
+			if err != nil {
+				rv.err = err
+				return
+			}
+			rv.allowed = result.Status.Allowed
+		}(rv)
+	}
+	wg.Wait()
+	return reviews
+}
+
+// rulesAllow reports whether resource rules grant a verb on every object of a tinysystems
+// resource. Rules limited to resource names don't.
+func rulesAllow(rules []authorizationv1.ResourceRule, verb, resource string) bool {
+	for _, rule := range rules {
+		if len(rule.ResourceNames) > 0 {
+			continue
+		}
+		if matchesRule(rule.Verbs, verb) && matchesRule(rule.APIGroups, v1alpha1.GroupVersion.Group) && matchesRule(rule.Resources, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRule reports whether a rule field lists a value or the wildcard.
+func matchesRule(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == "*" {
+			return true
+		}
+	}
+	return false
+}
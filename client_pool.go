@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const (
+	// poolIdleTTL is how long an unused manager or context config is kept alive
+	poolIdleTTL = 10 * time.Minute
+	// poolSweepInterval is how often idle entries are evicted
+	poolSweepInterval = time.Minute
+)
+
+// poolKey identifies a resource manager by kube context and namespace.
+type poolKey struct {
+	context   string
+	namespace string
+}
+
+// pooledContext holds the rest config and clients shared by all namespaces of a context.
+// It is published before it is loaded; ready is closed once config, clientset or err are set.
+type pooledContext struct {
+	ready     chan struct{}
+	err       error
+	config    *rest.Config
+	clientset *kubernetes.Clientset
+	// watchClient has no request timeout so long-running watches aren't cut off
+	watchMu     sync.Mutex
+	watchClient client.WithWatch
+	lastUsed    time.Time
+}
+
+// pooledManager is a resource manager kept alive between calls. Like pooledContext it is
+// published before it is built; ready is closed once mgr or err are set.
+type pooledManager struct {
+	ready    chan struct{}
+	err      error
+	mgr      *resource.Manager
+	lastUsed time.Time
+}
+
+// clientPool caches cluster clients and resource managers so bound methods
+// don't reload kubeconfig and rebuild clients on every call. Reusing the same
+// rest config also keeps exec credential plugins (gke-gcloud-auth-plugin etc.)
+// from being invoked for every request. Clients are built outside mu, so a slow or
+// unreachable context doesn't hold up lookups of the others; concurrent callers for
+// the same key wait for the one build in flight.
+type clientPool struct {
+	mu         sync.Mutex
+	contexts   map[string]*pooledContext
+	managers   map[poolKey]*pooledManager
+	nodeCaches map[nodeCacheKey]*projectNodeCache
+
+	// onWatchStatus receives connection state changes of node cache watches
+	onWatchStatus func(WatchStatus)
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func newClientPool() *clientPool {
+	return &clientPool{
+		contexts:   make(map[string]*pooledContext),
+		managers:   make(map[poolKey]*pooledManager),
+		nodeCaches: make(map[nodeCacheKey]*projectNodeCache),
+		stop:       make(chan struct{}),
+	}
+}
+
+// context returns the context entry, loading it if needed. Only the first caller
+// loads it, others wait for that load.
+func (p *clientPool) context(contextName string) (*pooledContext, error) {
+	p.mu.Lock()
+	pc, ok := p.contexts[contextName]
+	if !ok {
+		pc = &pooledContext{ready: make(chan struct{}), lastUsed: time.Now()}
+		p.contexts[contextName] = pc
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		pc.config, pc.clientset, pc.err = loadContextClients(contextName)
+		close(pc.ready)
+		if pc.err != nil {
+			// Failed loads aren't cached, the next call tries again
+			p.mu.Lock()
+			if p.contexts[contextName] == pc {
+				delete(p.contexts, contextName)
+			}
+			p.mu.Unlock()
+		}
+	}
+
+	<-pc.ready
+	if pc.err != nil {
+		return nil, pc.err
+	}
+	p.mu.Lock()
+	pc.lastUsed = time.Now()
+	p.mu.Unlock()
+	return pc, nil
+}
+
+// loadContextClients loads the rest config of a context and builds its clientset.
+func loadContextClients(contextName string) (*rest.Config, *kubernetes.Clientset, error) {
+	config, err := loadContextConfig(contextName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+	return config, clientset, nil
+}
+
+// config returns the cached rest config for a context.
+func (p *clientPool) config(contextName string) (*rest.Config, error) {
+	pc, err := p.context(contextName)
+	if err != nil {
+		return nil, err
+	}
+	return pc.config, nil
+}
+
+// clientset returns the cached Kubernetes clientset for a context.
+func (p *clientPool) clientset(contextName string) (*kubernetes.Clientset, error) {
+	pc, err := p.context(contextName)
+	if err != nil {
+		return nil, err
+	}
+	return pc.clientset, nil
+}
+
+// manager returns the cached resource manager for a context and namespace.
+func (p *clientPool) manager(contextName, namespace string) (*resource.Manager, error) {
+	key := poolKey{context: contextName, namespace: namespace}
+
+	p.mu.Lock()
+	pm, ok := p.managers[key]
+	if !ok {
+		pm = &pooledManager{ready: make(chan struct{}), lastUsed: time.Now()}
+		p.managers[key] = pm
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		pm.mgr, pm.err = p.buildManager(contextName, namespace)
+		close(pm.ready)
+		if pm.err != nil {
+			p.mu.Lock()
+			if p.managers[key] == pm {
+				delete(p.managers, key)
+			}
+			p.mu.Unlock()
+		}
+	}
+
+	<-pm.ready
+	if pm.err != nil {
+		return nil, pm.err
+	}
+	p.mu.Lock()
+	pm.lastUsed = time.Now()
+	if pc, ok := p.contexts[contextName]; ok {
+		pc.lastUsed = pm.lastUsed
+	}
+	p.mu.Unlock()
+	return pm.mgr, nil
+}
+
+// buildManager creates a resource manager from the context's rest config. Its client reports
+// every successful node write to the node caches, so reads after a write wait for the cache
+// to catch up instead of returning stale nodes.
+func (p *clientPool) buildManager(contextName, namespace string) (*resource.Manager, error) {
+	pc, err := p.context(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add scheme: %w", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add scheme: %w", err)
+	}
+
+	kubeClient, err := client.NewWithWatch(pc.config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client: %w", err)
+	}
+
+	kubeClient = interceptor.NewClient(kubeClient, interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if err := c.Create(ctx, obj, opts...); err != nil {
+				return err
+			}
+			p.noteNodeWrite(contextName, namespace, obj, false)
+			return nil
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if err := c.Update(ctx, obj, opts...); err != nil {
+				return err
+			}
+			p.noteNodeWrite(contextName, namespace, obj, false)
+			return nil
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if err := c.Patch(ctx, obj, patch, opts...); err != nil {
+				return err
+			}
+			p.noteNodeWrite(contextName, namespace, obj, false)
+			return nil
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			if err := c.Delete(ctx, obj, opts...); err != nil {
+				return err
+			}
+			p.noteNodeWrite(contextName, namespace, obj, true)
+			return nil
+		},
+	})
+
+	return resource.NewManagerFromClient(kubeClient, namespace)
+}
+
+// watchClient returns the watch-capable client of a context, building it on first use.
+func (p *clientPool) watchClient(contextName string) (client.WithWatch, error) {
+	pc, err := p.context(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	pc.watchMu.Lock()
+	defer pc.watchMu.Unlock()
+	if pc.watchClient != nil {
+		return pc.watchClient, nil
+	}
+
+	config := rest.CopyConfig(pc.config)
+	config.Timeout = 0
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add scheme: %w", err)
+	}
+
+	watchClient, err := client.NewWithWatch(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client: %w", err)
+	}
+	pc.watchClient = watchClient
+	return watchClient, nil
+}
+
+// nodeCache returns the node cache of a project, starting its informer on first use,
+// and waits until the initial list is loaded.
+func (p *clientPool) nodeCache(ctx context.Context, contextName, namespace, projectName string) (*projectNodeCache, error) {
+	key := nodeCacheKey{context: contextName, namespace: namespace, project: projectName}
+
+	p.mu.Lock()
+	nc, ok := p.nodeCaches[key]
+	p.mu.Unlock()
+	if !ok {
+		watchClient, err := p.watchClient(contextName)
+		if err != nil {
+			return nil, err
+		}
+		p.mu.Lock()
+		if nc, ok = p.nodeCaches[key]; !ok {
+			nc = newProjectNodeCache(key, watchClient, p.onWatchStatus)
+			nc.start()
+			p.nodeCaches[key] = nc
+		}
+		p.mu.Unlock()
+	}
+
+	nc.touch()
+	if err := nc.waitForSync(ctx); err != nil {
+		// Drop the cache so the next call starts over with a fresh informer
+		p.mu.Lock()
+		if p.nodeCaches[key] == nc {
+			delete(p.nodeCaches, key)
+			nc.stop()
+		}
+		p.mu.Unlock()
+		return nil, err
+	}
+	return nc, nil
+}
+
+// existingNodeCache returns the node cache of a project if one is running.
+func (p *clientPool) existingNodeCache(contextName, namespace, projectName string) *projectNodeCache {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.nodeCaches[nodeCacheKey{context: contextName, namespace: namespace, project: projectName}]
+}
+
+// noteNodeWrite tells the running node caches of a namespace about a node the app has just
+// written, so reads wait until the cache has seen that write. Deletes may come without labels,
+// so they are reported to every cache of the namespace.
+func (p *clientPool) noteNodeWrite(contextName, namespace string, obj client.Object, deleted bool) {
+	node, ok := obj.(*v1alpha1.TinyNode)
+	if !ok {
+		return
+	}
+	if node.Namespace != "" {
+		namespace = node.Namespace
+	}
+	write := nodeWrite{resourceVersion: node.ResourceVersion, deleted: deleted, at: time.Now()}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, nc := range p.nodeCaches {
+		if key.context != contextName || key.namespace != namespace {
+			continue
+		}
+		if deleted || key.project == node.Labels[v1alpha1.ProjectNameLabel] {
+			nc.expectWrite(node.Name, write)
+		}
+	}
+}
+
+// invalidate drops every cached client so the next call reloads kubeconfig and credentials.
+func (p *clientPool) invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, nc := range p.nodeCaches {
+		nc.stop()
+	}
+	p.contexts = make(map[string]*pooledContext)
+	p.managers = make(map[poolKey]*pooledManager)
+	p.nodeCaches = make(map[nodeCacheKey]*projectNodeCache)
+}
+
+// invalidateContext drops cached clients of a single context.
+func (p *clientPool) invalidateContext(contextName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.contexts, contextName)
+	for key := range p.managers {
+		if key.context == contextName {
+			delete(p.managers, key)
+		}
+	}
+	for key, nc := range p.nodeCaches {
+		if key.context == contextName {
+			nc.stop()
+			delete(p.nodeCaches, key)
+		}
+	}
+}
+
+// evictIdle removes entries that haven't been used within ttl.
+func (p *clientPool) evictIdle(ttl time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	deadline := time.Now().Add(-ttl)
+	for key, nc := range p.nodeCaches {
+		if !nc.pinned() && nc.idleSince().Before(deadline) {
+			nc.stop()
+			delete(p.nodeCaches, key)
+		}
+	}
+	for key, pm := range p.managers {
+		if pm.lastUsed.Before(deadline) {
+			delete(p.managers, key)
+		}
+	}
+	for name, pc := range p.contexts {
+		if pc.lastUsed.Before(deadline) {
+			delete(p.contexts, name)
+		}
+	}
+}
+
+// run periodically evicts idle entries until close is called.
+func (p *clientPool) run() {
+	ticker := time.NewTicker(poolSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.evictIdle(poolIdleTTL)
+		}
+	}
+}
+
+// close stops the eviction loop and drops all cached clients.
+func (p *clientPool) close() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+	p.invalidate()
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+const (
+	// healthProbeInterval is how often the active context is probed
+	healthProbeInterval = 15 * time.Second
+	// healthProbeTimeout bounds a single probe, well below the client's request timeout
+	healthProbeTimeout = 5 * time.Second
+	// healthSlowRTT is the round trip above which the connection is reported as degraded
+	healthSlowRTT = 2 * time.Second
+)
+
+// Cluster health states
+const (
+	healthHealthy      = "healthy"
+	healthDegraded     = "degraded"     // reachable but slow
+	healthUnauthorized = "unauthorized" // credentials rejected, even after a refresh
+	healthUnreachable  = "unreachable"
+)
+
+// ClusterHealth is the outcome of the last probe of the active context. It is emitted as a
+// cluster:health event after every probe.
+type ClusterHealth struct {
+	Context       string `json:"context"`
+	Namespace     string `json:"namespace"`
+	State         string `json:"state"`
+	ServerVersion string `json:"serverVersion,omitempty"`
+	RTTMillis     int64  `json:"rttMillis"`
+	LastError     string `json:"lastError,omitempty"`
+	LoginCommand  string `json:"loginCommand,omitempty"` // e.g. "gcloud auth login" when credentials need a new login
+	Failures      int    `json:"failures"`               // consecutive failed probes
+	CheckedAt     int64  `json:"checkedAt"`              // unix milliseconds
+	LastHealthyAt int64  `json:"lastHealthyAt"`          // unix milliseconds, 0 if never
+}
+
+// healthMonitor probes one context in the background.
+type healthMonitor struct {
+	mu          sync.Mutex
+	contextName string
+	namespace   string
+	cancel      context.CancelFunc
+	last        *ClusterHealth
+}
+
+// StartHealthMonitor starts probing a context, replacing the monitor of the previously
+// active one. The first probe runs right away.
+func (a *App) StartHealthMonitor(contextName, namespace string) {
+	a.health.mu.Lock()
+	defer a.health.mu.Unlock()
+
+	if a.health.cancel != nil && a.health.contextName == contextName && a.health.namespace == namespace {
+		return
+	}
+	if a.health.cancel != nil {
+		a.health.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.health.contextName = contextName
+	a.health.namespace = namespace
+	a.health.cancel = cancel
+	a.health.last = nil
+
+	go a.runHealthMonitor(ctx, contextName, namespace)
+}
+
+// StopHealthMonitor stops probing, e.g. when no context is selected.
+func (a *App) StopHealthMonitor() {
+	a.health.mu.Lock()
+	defer a.health.mu.Unlock()
+
+	if a.health.cancel != nil {
+		a.health.cancel()
+	}
+	a.health.cancel = nil
+	a.health.last = nil
+}
+
+// GetClusterHealth returns the last probe of the active context, nil before the first one.
+func (a *App) GetClusterHealth() *ClusterHealth {
+	a.health.mu.Lock()
+	defer a.health.mu.Unlock()
+
+	return a.health.last
+}
+
+func (a *App) runHealthMonitor(ctx context.Context, contextName, namespace string) {
+	ticker := time.NewTicker(healthProbeInterval)
+	defer ticker.Stop()
+
+	var prev *ClusterHealth
+	for {
+		health := a.probeHealth(ctx, contextName, namespace, prev)
+
+		// A probe finishing after the monitor was replaced or stopped is dropped
+		a.health.mu.Lock()
+		if ctx.Err() != nil {
+			a.health.mu.Unlock()
+			return
+		}
+		a.health.last = health
+		a.health.mu.Unlock()
+		if a.ctx != nil {
+			wailsruntime.EventsEmit(a.ctx, "cluster:health", health)
+		}
+		if health.State != healthHealthy && (prev == nil || prev.State != health.State) {
+			a.logger.Info("cluster health changed", "context", contextName, "state", health.State, "error", health.LastError)
+		}
+		prev = health
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeHealth fetches the server version and runs an access review, which every
+// authenticated user may do. On 401 the credentials of the context are refreshed and the
+// probe retried once; a context that stays unauthorized isn't refreshed again on every probe.
+func (a *App) probeHealth(ctx context.Context, contextName, namespace string, prev *ClusterHealth) *ClusterHealth {
+	health := &ClusterHealth{
+		Context:   contextName,
+		Namespace: namespace,
+		CheckedAt: time.Now().UnixMilli(),
+	}
+	if prev != nil {
+		health.LastHealthyAt = prev.LastHealthyAt
+		health.ServerVersion = prev.ServerVersion
+	}
+
+	rtt, serverVersion, err := a.probeOnce(ctx, contextName, namespace)
+	if isCredentialError(err) && (prev == nil || prev.State != healthUnauthorized) {
+		a.logger.Info("cluster probe unauthorized, refreshing credentials", "context", contextName)
+		refresh, refreshErr := a.refreshContextAuth(contextName)
+		switch {
+		case refreshErr != nil:
+			err = fmt.Errorf("failed to refresh credentials: %w", refreshErr)
+		case refresh.LoginRequired:
+			err = refresh.loginRequired()
+			health.LoginCommand = refresh.LoginCommand
+		default:
+			rtt, serverVersion, err = a.probeOnce(ctx, contextName, namespace)
+		}
+	} else if isCredentialError(err) && prev != nil {
+		health.LoginCommand = prev.LoginCommand
+	}
+
+	health.RTTMillis = rtt.Milliseconds()
+	if serverVersion != "" {
+		health.ServerVersion = serverVersion
+	}
+	switch {
+	case err == nil && rtt > healthSlowRTT:
+		health.State = healthDegraded
+	case err == nil:
+		health.State = healthHealthy
+	case isCredentialError(err):
+		health.State = healthUnauthorized
+	default:
+		health.State = healthUnreachable
+	}
+
+	if err != nil {
+		health.LastError = err.Error()
+		health.Failures = 1
+		if prev != nil {
+			health.Failures = prev.Failures + 1
+		}
+	} else {
+		health.LastHealthyAt = health.CheckedAt
+	}
+	return health
+}
+
+// probeOnce returns the round trip of the version request, and the server version.
+func (a *App) probeOnce(ctx context.Context, contextName, namespace string) (time.Duration, string, error) {
+	clientset, err := a.clients.clientset(contextName)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to build client configuration: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	raw, err := clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	rtt := time.Since(start)
+	if err != nil {
+		return rtt, "", probeError("version", err)
+	}
+	var info version.Info
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return rtt, "", fmt.Errorf("invalid version response: %w", err)
+	}
+
+	// /version is often served to anonymous users, the review checks the credentials
+	ssar := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "list",
+				Group:     v1alpha1.GroupVersion.Group,
+				Resource:  "tinynodes",
+			},
+		},
+	}
+	if _, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, v1.CreateOptions{}); err != nil {
+		return rtt, info.GitVersion, probeError("access review", err)
+	}
+	return rtt, info.GitVersion, nil
+}
+
+// probeError names the failed request, reporting a timeout as such.
+func probeError(request string, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%s request timed out after %s: %w", request, healthProbeTimeout, err)
+	}
+	return fmt.Errorf("%s request failed: %w", request, err)
+}
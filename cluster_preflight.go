@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Preflight check statuses
+const (
+	preflightOK      = "ok"
+	preflightWarning = "warning"
+	preflightFail    = "fail"
+)
+
+const preflightCRDInstall = "helm repo add tinysystems https://tiny-systems.github.io/module/ && helm upgrade --install tinysystems-crd tinysystems/tinysystems-crd --namespace %s --create-namespace"
+
+// preflightResource is a tinysystems resource the client needs, and the verbs it uses on it.
+type preflightResource struct {
+	kind     string
+	resource string
+	verbs    []string
+}
+
+var preflightVerbsReadWrite = []string{"get", "list", "watch", "create", "update", "delete"}
+
+var preflightResources = []preflightResource{
+	{kind: "TinyProject", resource: "tinyprojects", verbs: preflightVerbsReadWrite},
+	{kind: "TinyFlow", resource: "tinyflows", verbs: preflightVerbsReadWrite},
+	{kind: "TinyNode", resource: "tinynodes", verbs: preflightVerbsReadWrite},
+	{kind: "TinyWidgetPage", resource: "tinywidgetpages", verbs: preflightVerbsReadWrite},
+	{kind: "TinyScenario", resource: "tinyscenarios", verbs: preflightVerbsReadWrite},
+	{kind: "TinySignal", resource: "tinysignals", verbs: preflightVerbsReadWrite},
+	{kind: "TinyModule", resource: "tinymodules", verbs: []string{"get", "list", "watch"}},
+}
+
+// PreflightItem is one readiness check, with a hint on how to fix it when it doesn't pass.
+type PreflightItem struct {
+	Category    string `json:"category"` // cluster, crd, rbac or operator
+	Name        string `json:"name"`
+	Status      string `json:"status"` // ok, warning or fail
+	Message     string `json:"message,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// PreflightReport tells whether a namespace is ready to be used by the client.
+type PreflightReport struct {
+	Context   string          `json:"context"`
+	Namespace string          `json:"namespace"`
+	Ready     bool            `json:"ready"`
+	Failures  int             `json:"failures"`
+	Warnings  int             `json:"warnings"`
+	Items     []PreflightItem `json:"items"`
+}
+
+// PreflightCheck verifies that the tinysystems CRDs are served at the version the client
+// uses, that the current user may use them in the namespace and that module operators run
+// there. Every check is reported, so one failure doesn't hide the next.
+func (a *App) PreflightCheck(contextName, namespace string) (*PreflightReport, error) {
+	clientset, err := a.clients.clientset(contextName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client configuration for context '%s': %w", contextName, err)
+	}
+
+	report := &PreflightReport{Context: contextName, Namespace: namespace}
+	report.Items = append(report.Items, a.preflightNamespace(clientset, namespace))
+
+	crdItems, served := preflightCRDs(clientset, namespace)
+	report.Items = append(report.Items, crdItems...)
+	report.Items = append(report.Items, a.preflightRBAC(clientset, namespace)...)
+	if served["TinyModule"] {
+		report.Items = append(report.Items, a.preflightOperators(clientset, contextName, namespace)...)
+	}
+
+	for _, item := range report.Items {
+		switch item.Status {
+		case preflightFail:
+			report.Failures++
+		case preflightWarning:
+			report.Warnings++
+		}
+	}
+	report.Ready = report.Failures == 0
+
+	a.logger.Info("preflight check finished", "context", contextName, "namespace", namespace, "failures", report.Failures, "warnings", report.Warnings)
+	return report, nil
+}
+
+// preflightNamespace checks that the namespace exists.
+func (a *App) preflightNamespace(clientset *kubernetes.Clientset, namespace string) PreflightItem {
+	item := PreflightItem{Category: "cluster", Name: "Namespace " + namespace, Status: preflightOK}
+	_, err := clientset.CoreV1().Namespaces().Get(a.ctx, namespace, v1.GetOptions{})
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		item.Status = preflightFail
+		item.Message = "The namespace doesn't exist"
+		item.Remediation = fmt.Sprintf("Create it with kubectl create namespace %s", namespace)
+	case apierrors.IsForbidden(err):
+		// Namespaced roles often can't read the namespace object itself
+		item.Status = preflightWarning
+		item.Message = "Not allowed to read the namespace, can't tell whether it exists"
+	default:
+		item.Status = preflightFail
+		item.Message = err.Error()
+		item.Remediation = "Check that the cluster is reachable and your credentials are valid"
+	}
+	return item
+}
+
+// preflightCRDs checks that every tinysystems resource is served at the API version the client
+// uses. It returns the kinds that are.
+func preflightCRDs(clientset *kubernetes.Clientset, namespace string) ([]PreflightItem, map[string]bool) {
+	gv := v1alpha1.GroupVersion
+	install := fmt.Sprintf(preflightCRDInstall, namespace)
+
+	groups, err := clientset.Discovery().ServerGroups()
+	if err != nil {
+		return []PreflightItem{{
+			Category:    "crd",
+			Name:        gv.Group,
+			Status:      preflightFail,
+			Message:     fmt.Sprintf("Unable to discover API groups: %v", err),
+			Remediation: "Check that the cluster is reachable and your credentials are valid",
+		}}, nil
+	}
+
+	var servedVersions []string
+	for _, g := range groups.Groups {
+		if g.Name != gv.Group {
+			continue
+		}
+		for _, v := range g.Versions {
+			servedVersions = append(servedVersions, v.Version)
+		}
+	}
+	if len(servedVersions) == 0 {
+		return []PreflightItem{{
+			Category:    "crd",
+			Name:        gv.Group,
+			Status:      preflightFail,
+			Message:     "The tinysystems CRDs are not installed",
+			Remediation: install,
+		}}, nil
+	}
+	if !slices.Contains(servedVersions, gv.Version) {
+		return []PreflightItem{{
+			Category:    "crd",
+			Name:        gv.Group,
+			Status:      preflightFail,
+			Message:     fmt.Sprintf("The cluster serves %s, this client needs %s", strings.Join(servedVersions, ", "), gv.Version),
+			Remediation: "Upgrade the desktop client, or install CRDs matching it: " + install,
+		}}, nil
+	}
+
+	resources, err := clientset.Discovery().ServerResourcesForGroupVersion(gv.String())
+	if err != nil {
+		return []PreflightItem{{
+			Category:    "crd",
+			Name:        gv.String(),
+			Status:      preflightFail,
+			Message:     fmt.Sprintf("Unable to list resources: %v", err),
+			Remediation: install,
+		}}, nil
+	}
+	served := make(map[string]bool)
+	for _, r := range resources.APIResources {
+		served[r.Kind] = true
+	}
+
+	var items []PreflightItem
+	for _, r := range preflightResources {
+		item := PreflightItem{Category: "crd", Name: r.kind, Status: preflightOK, Message: gv.String()}
+		if !served[r.kind] {
+			item.Status = preflightFail
+			item.Message = fmt.Sprintf("%s is not served by %s", r.kind, gv.String())
+			item.Remediation = "Upgrade the tinysystems CRDs: " + install
+		}
+		items = append(items, item)
+	}
+	return items, served
+}
+
+// preflightRBAC asks the API server which verbs the current user may use on each resource.
+func (a *App) preflightRBAC(clientset *kubernetes.Clientset, namespace string) []PreflightItem {
+	reviews := reviewAccess(a.ctx, clientset, namespace)
+
+	var items []PreflightItem
+	for _, r := range preflightResources {
+		var denied []string
+		var reviewErr error
+		for _, rv := range reviews {
+			if rv.resource != r.resource {
+				continue
+			}
+			if rv.err != nil {
+				reviewErr = rv.err
+			} else if !rv.allowed {
+				denied = append(denied, rv.verb)
+			}
+		}
+
+		item := PreflightItem{Category: "rbac", Name: r.kind, Status: preflightOK, Message: strings.Join(r.verbs, ", ")}
+		switch {
+		case reviewErr != nil:
+			item.Status = preflightWarning
+			item.Message = fmt.Sprintf("Unable to review permissions: %v", reviewErr)
+		case len(denied) > 0:
+			item.Status = preflightFail
+			item.Message = "Not allowed to " + strings.Join(denied, ", ")
+			item.Remediation = fmt.Sprintf("Ask a cluster admin for a Role in namespace %s granting %s on %s.%s",
+				namespace, strings.Join(denied, ", "), r.resource, v1alpha1.GroupVersion.Group)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// preflightOperators checks that modules have registered in the namespace and that their
+// operator deployments have ready replicas.
+func (a *App) preflightOperators(clientset *kubernetes.Clientset, contextName, namespace string) []PreflightItem {
+	mgr, err := a.getManager(contextName, namespace)
+	if err != nil {
+		return []PreflightItem{{Category: "operator", Name: "Modules", Status: preflightFail, Message: err.Error()}}
+	}
+	modules, err := mgr.GetInstalledComponents(a.ctx)
+	if err != nil {
+		return []PreflightItem{{Category: "operator", Name: "Modules", Status: preflightFail, Message: fmt.Sprintf("Unable to list modules: %v", err)}}
+	}
+	if len(modules) == 0 {
+		return []PreflightItem{{
+			Category:    "operator",
+			Name:        "Modules",
+			Status:      preflightFail,
+			Message:     "No module operators are registered in the namespace, flows can't run",
+			Remediation: "Install modules into the namespace, see https://tinysystems.io/modules",
+		}}
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+
+	deployments, deployErr := clientset.AppsV1().Deployments(namespace).List(a.ctx, v1.ListOptions{})
+
+	var items []PreflightItem
+	for _, mod := range modules {
+		item := PreflightItem{Category: "operator", Name: mod.Name, Status: preflightOK, Message: mod.Version}
+		if deployErr != nil {
+			item.Status = preflightWarning
+			item.Message = fmt.Sprintf("Registered, unable to check its deployment: %v", deployErr)
+			items = append(items, item)
+			continue
+		}
+
+		// Operator deployments are named after their module
+		name := path.Base(mod.Name)
+		found := false
+		for _, d := range deployments.Items {
+			if !strings.Contains(d.Name, name) {
+				continue
+			}
+			found = true
+			if d.Status.ReadyReplicas == 0 {
+				item.Status = preflightFail
+				item.Message = fmt.Sprintf("Deployment %s has no ready replicas", d.Name)
+				item.Remediation = fmt.Sprintf("Inspect it with kubectl -n %s describe deployment %s", namespace, d.Name)
+			}
+			break
+		}
+		if !found {
+			item.Status = preflightWarning
+			item.Message = "Registered, but no deployment for it was found in the namespace"
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/resource"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// maxEditHistory is how many operations can be undone
+	maxEditHistory = 100
+	// editNodeGoneTimeout bounds how long recreating a node waits for its terminating predecessor
+	editNodeGoneTimeout = 30 * time.Second
+)
+
+// nodeChange is the state of a node before and after an edit, nil where the node didn't exist.
+type nodeChange struct {
+	name   string
+	before *v1alpha1.TinyNode
+	after  *v1alpha1.TinyNode
+}
+
+// editOperation is a single editor mutation, which may change several nodes.
+type editOperation struct {
+	description string
+	changes     []nodeChange
+}
+
+// editHistory is the undo and redo stack of the flow open in the editor.
+type editHistory struct {
+	contextName      string
+	namespace        string
+	projectName      string
+	flowResourceName string
+	undo             []editOperation
+	redo             []editOperation
+	stepping         bool // an undo or redo is being applied
+}
+
+// covers reports whether a node state belongs to, or is shared with, the session's flow.
+func (h *editHistory) covers(node *v1alpha1.TinyNode) bool {
+	if node == nil || node.Labels[v1alpha1.ProjectNameLabel] != h.projectName {
+		return false
+	}
+	return node.Labels[v1alpha1.FlowNameLabel] == h.flowResourceName ||
+		containsFlow(node.Annotations[v1alpha1.SharedWithFlowsAnnotation], h.flowResourceName)
+}
+
+// EditHistoryState tells the editor what can be undone and redone. It is also emitted as
+// an edit:history event whenever it changes.
+type EditHistoryState struct {
+	CanUndo bool   `json:"canUndo"`
+	CanRedo bool   `json:"canRedo"`
+	Undo    string `json:"undo,omitempty"` // description of the operation Undo reverts
+	Redo    string `json:"redo,omitempty"` // description of the operation Redo applies again
+}
+
+// BeginEditSession starts an empty undo history for a flow opened in the editor. Edits in
+// other contexts or namespaces aren't recorded until the next session.
+func (a *App) BeginEditSession(contextName, namespace, projectName, flowResourceName string) {
+	a.historyMu.Lock()
+	defer a.historyMu.Unlock()
+
+	a.history = &editHistory{
+		contextName:      contextName,
+		namespace:        namespace,
+		projectName:      projectName,
+		flowResourceName: flowResourceName,
+	}
+}
+
+// EndEditSession drops the undo history when the editor closes. If the flow was edited,
+// its new state is saved as a flow revision.
+func (a *App) EndEditSession() {
+	a.historyMu.Lock()
+	h := a.history
+	a.history = nil
+	a.historyMu.Unlock()
+
+	if h == nil || len(h.undo) == 0 {
+		return
+	}
+	if _, err := a.SaveFlowRevision(h.contextName, h.namespace, h.projectName, h.flowResourceName, fmt.Sprintf("%d edits in the editor", len(h.undo))); err != nil {
+		a.logger.Error(err, "failed to save flow revision after editing", "flow", h.flowResourceName)
+	}
+}
+
+// GetEditHistory returns what can be undone and redone in the current edit session.
+func (a *App) GetEditHistory() EditHistoryState {
+	a.historyMu.Lock()
+	defer a.historyMu.Unlock()
+
+	return a.historyState()
+}
+
+// Undo reverts the last edit of the session. If a node it touched has been changed since,
+// by someone else or outside the editor, nothing is reverted and the operation is dropped
+// from the history.
+func (a *App) Undo() (EditHistoryState, error) {
+	state, err := a.stepHistory(true)
+	a.emitHistoryState(state)
+	return state, err
+}
+
+// Redo applies the last undone edit again, with the same conflict check as Undo. If an undo
+// or redo fails halfway, the part that was applied is kept in the history so it can be
+// stepped back.
+func (a *App) Redo() (EditHistoryState, error) {
+	state, err := a.stepHistory(false)
+	a.emitHistoryState(state)
+	return state, err
+}
+
+// stepHistory undoes or redoes the last operation. historyMu is only held to take the
+// operation off its stack and to put it back, not while nodes are read and written.
+func (a *App) stepHistory(undo bool) (EditHistoryState, error) {
+	a.historyMu.Lock()
+	h := a.history
+	if h == nil {
+		a.historyMu.Unlock()
+		return EditHistoryState{}, fmt.Errorf("no edit session")
+	}
+	verb := "undo"
+	from, to := &h.undo, &h.redo
+	if !undo {
+		verb = "redo"
+		from, to = &h.redo, &h.undo
+	}
+	if h.stepping || len(*from) == 0 {
+		state := a.historyState()
+		a.historyMu.Unlock()
+		if h.stepping {
+			return state, fmt.Errorf("cannot %s: another undo or redo is in progress", verb)
+		}
+		return state, fmt.Errorf("nothing to %s", verb)
+	}
+	op := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	h.stepping = true
+	a.historyMu.Unlock()
+
+	applied, err := a.applyEditOperation(h, op, undo)
+
+	a.historyMu.Lock()
+	defer a.historyMu.Unlock()
+	h.stepping = false
+	if a.history != h {
+		// The session ended meanwhile
+		return a.historyState(), err
+	}
+
+	var conflict *editConflictError
+	switch {
+	case err == nil:
+		*to = append(*to, op)
+		a.logger.Info("edit history step", "flow", h.flowResourceName, "undo", undo, "operation", op.description, "nodes", applied)
+	case errors.As(err, &conflict):
+		// The operation can't be stepped over anymore and is dropped
+	case applied == 0:
+		*from = append(*from, op)
+	default:
+		// Split the operation: the applied part can be stepped back, the rest retried
+		description := op.description
+		if !strings.HasSuffix(description, " (partial)") {
+			description += " (partial)"
+		}
+		done := editOperation{description: description}
+		rest := editOperation{description: description}
+		if undo {
+			done.changes, rest.changes = op.changes[len(op.changes)-applied:], op.changes[:len(op.changes)-applied]
+		} else {
+			done.changes, rest.changes = op.changes[:applied], op.changes[applied:]
+		}
+		*to = append(*to, done)
+		*from = append(*from, rest)
+		a.logger.Info("edit history step partially applied", "flow", h.flowResourceName, "undo", undo, "operation", op.description, "applied", applied, "nodes", len(op.changes))
+		err = fmt.Errorf("%w (%d of %d node changes applied)", err, applied, len(op.changes))
+	}
+	return a.historyState(), err
+}
+
+// editConflictError is returned when a node changed since an operation touched it.
+type editConflictError struct {
+	verb        string
+	description string
+	node        string
+}
+
+func (e *editConflictError) Error() string {
+	return fmt.Sprintf("cannot %s %q: node %s has been changed since", e.verb, e.description, e.node)
+}
+
+// applyEditOperation undoes or redoes an operation and returns how many of its node changes
+// were applied. Every node is checked first, so a conflict leaves the cluster untouched.
+func (a *App) applyEditOperation(h *editHistory, op editOperation, undo bool) (int, error) {
+	verb := "undo"
+	if !undo {
+		verb = "redo"
+	}
+
+	// Undo goes from the state after the edit to the one before it, redo the other way
+	steps := make([]nodeChange, 0, len(op.changes))
+	if undo {
+		for i := len(op.changes) - 1; i >= 0; i-- {
+			c := op.changes[i]
+			steps = append(steps, nodeChange{name: c.name, before: c.after, after: c.before})
+		}
+	} else {
+		steps = op.changes
+	}
+
+	if err := a.requireWritable(h.contextName, h.namespace, stepAccess(steps)...); err != nil {
+		return 0, err
+	}
+	mgr, err := a.getManager(h.contextName, h.namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	current := make([]*v1alpha1.TinyNode, len(steps))
+	seen := make(map[string]bool)
+	for i, step := range steps {
+		if seen[step.name] {
+			// Later steps on the same node start from what the earlier one left
+			continue
+		}
+		seen[step.name] = true
+
+		node, err := mgr.GetNode(a.ctx, step.name, h.namespace)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return 0, fmt.Errorf("get node %s: %w", step.name, err)
+		}
+		if err != nil || node.DeletionTimestamp != nil {
+			// A terminating node is as good as gone
+			node = nil
+		}
+		if !sameNodeContent(node, step.before) {
+			return 0, &editConflictError{verb: verb, description: op.description, node: step.name}
+		}
+		current[i] = node
+	}
+
+	for i, step := range steps {
+		if err := a.applyNodeState(mgr, h.namespace, step.name, current[i], step.after); err != nil {
+			return i, fmt.Errorf("%s %q: %w", verb, op.description, err)
+		}
+	}
+	return len(steps), nil
+}
+
+// stepAccess returns the accesses needed to apply node changes.
+func stepAccess(steps []nodeChange) []access {
+	needs := make(map[access]bool)
+	for _, step := range steps {
+		switch {
+		case step.after == nil:
+			needs[deleteNodes] = true
+		case step.before == nil:
+			needs[createNodes] = true
+		default:
+			needs[updateNodes] = true
+		}
+	}
+	result := make([]access, 0, len(needs))
+	for _, need := range []access{createNodes, updateNodes, deleteNodes} {
+		if needs[need] {
+			result = append(result, need)
+		}
+	}
+	return result
+}
+
+// applyNodeState puts a node into the given state, creating or deleting it as needed.
+// current is the node as read from the cluster, nil to read it again. A node that is still
+// terminating counts as deleted; recreating it waits until it is gone.
+func (a *App) applyNodeState(mgr *resource.Manager, namespace, name string, current, target *v1alpha1.TinyNode) error {
+	if current == nil {
+		node, err := mgr.GetNode(a.ctx, name, namespace)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("get node %s: %w", name, err)
+		}
+		if err == nil && node.DeletionTimestamp == nil {
+			current = node
+		}
+		if err == nil && node.DeletionTimestamp != nil && target != nil {
+			if err := a.waitForNodeGone(mgr, namespace, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The history keeps its snapshot, whatever the client does with the object
+	target = target.DeepCopy()
+
+	switch {
+	case target == nil && current == nil:
+		return nil
+	case target == nil:
+		if err := mgr.DeleteNode(a.ctx, current); err != nil {
+			return fmt.Errorf("delete node %s: %w", name, err)
+		}
+	case current == nil:
+		// Owners and finalizers of the deleted node aren't carried over, an owner may be gone
+		// and finalizers are added by the controllers that handle them
+		node := &v1alpha1.TinyNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        target.Name,
+				Namespace:   target.Namespace,
+				Labels:      target.Labels,
+				Annotations: target.Annotations,
+			},
+			Spec: target.Spec,
+		}
+		if err := mgr.CreateNode(a.ctx, node); err != nil {
+			return fmt.Errorf("create node %s: %w", name, err)
+		}
+	default:
+		current.Labels = target.Labels
+		current.Annotations = target.Annotations
+		current.Spec = target.Spec
+		if err := mgr.UpdateNode(a.ctx, current); err != nil {
+			return fmt.Errorf("update node %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// waitForNodeGone waits until a terminating node has been removed, so it can be recreated
+// under the same name.
+func (a *App) waitForNodeGone(mgr *resource.Manager, namespace, name string) error {
+	err := wait.PollUntilContextTimeout(a.ctx, importPollInterval, editNodeGoneTimeout, true, func(ctx context.Context) (bool, error) {
+		_, err := mgr.GetNode(ctx, name, namespace)
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("node %s is still being deleted: %w", name, err)
+	}
+	return nil
+}
+
+// recordEdit adds an operation to the undo history if it belongs to the current edit session:
+// same context and namespace, and a node of the session's flow among the changes. Changes
+// that left a node as it was are left out. Recording a new edit clears the redo stack.
+func (a *App) recordEdit(contextName, namespace, description string, changes ...nodeChange) {
+	a.historyMu.Lock()
+	h := a.history
+	if h == nil || h.contextName != contextName || h.namespace != namespace {
+		a.historyMu.Unlock()
+		return
+	}
+
+	op := editOperation{description: description}
+	inFlow := false
+	for _, c := range changes {
+		if sameNodeContent(c.before, c.after) {
+			continue
+		}
+		op.changes = append(op.changes, c)
+		inFlow = inFlow || h.covers(c.before) || h.covers(c.after)
+	}
+	if len(op.changes) == 0 || !inFlow {
+		a.historyMu.Unlock()
+		return
+	}
+
+	h.undo = append(h.undo, op)
+	if len(h.undo) > maxEditHistory {
+		h.undo = h.undo[len(h.undo)-maxEditHistory:]
+	}
+	h.redo = nil
+	state := a.historyState()
+	a.historyMu.Unlock()
+
+	a.emitHistoryState(state)
+}
+
+// emitHistoryState tells the editor the history changed.
+func (a *App) emitHistoryState(state EditHistoryState) {
+	wailsruntime.EventsEmit(a.ctx, "edit:history", state)
+}
+
+// historyState returns the state of the current history. historyMu must be held.
+func (a *App) historyState() EditHistoryState {
+	h := a.history
+	if h == nil {
+		return EditHistoryState{}
+	}
+	var state EditHistoryState
+	if len(h.undo) > 0 {
+		state.CanUndo = true
+		state.Undo = h.undo[len(h.undo)-1].description
+	}
+	if len(h.redo) > 0 {
+		state.CanRedo = true
+		state.Redo = h.redo[len(h.redo)-1].description
+	}
+	return state
+}
+
+// snapshotNodes copies nodes by name, to diff them with diffNodeSnapshots after an edit.
+func snapshotNodes(nodes []v1alpha1.TinyNode) map[string]*v1alpha1.TinyNode {
+	snapshot := make(map[string]*v1alpha1.TinyNode, len(nodes))
+	for i := range nodes {
+		snapshot[nodes[i].Name] = nodes[i].DeepCopy()
+	}
+	return snapshot
+}
+
+// diffNodeSnapshots returns the nodes that were created, deleted or changed between two snapshots.
+func diffNodeSnapshots(before, after map[string]*v1alpha1.TinyNode) []nodeChange {
+	var changes []nodeChange
+	for name, b := range before {
+		if a := after[name]; !sameNodeContent(b, a) {
+			changes = append(changes, nodeChange{name: name, before: b, after: a})
+		}
+	}
+	for name, a := range after {
+		if _, ok := before[name]; !ok {
+			changes = append(changes, nodeChange{name: name, after: a})
+		}
+	}
+	return changes
+}
+
+// sameNodeContent reports whether two node states have the same labels, annotations and spec.
+// Resource versions are ignored, the controller bumps them with every status update.
+func sameNodeContent(a, b *v1alpha1.TinyNode) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return equality.Semantic.DeepEqual(a.Labels, b.Labels) &&
+		equality.Semantic.DeepEqual(a.Annotations, b.Annotations) &&
+		equality.Semantic.DeepEqual(a.Spec, b.Spec)
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// execPluginTimeout bounds a credential plugin run, which may refresh tokens over the network
+const execPluginTimeout = 30 * time.Second
+
+// authStampEnv is passed to exec credential plugins. client-go caches authenticators by
+// their exec config, so a new value makes it run the plugin again for that context.
+const authStampEnv = "TINY_AUTH_TS"
+
+// authStamps holds, per context, when its credentials were last refreshed.
+var authStamps = struct {
+	sync.Mutex
+	m map[string]int64
+}{m: make(map[string]int64)}
+
+// authStamp returns the refresh stamp of a context.
+func authStamp(contextName string) int64 {
+	authStamps.Lock()
+	defer authStamps.Unlock()
+
+	stamp, ok := authStamps.m[contextName]
+	if !ok {
+		stamp = time.Now().UnixNano()
+		authStamps.m[contextName] = stamp
+	}
+	return stamp
+}
+
+// bumpAuthStamp makes the next client of a context use fresh exec credentials.
+func bumpAuthStamp(contextName string) {
+	authStamps.Lock()
+	defer authStamps.Unlock()
+
+	authStamps.m[contextName] = time.Now().UnixNano()
+}
+
+// resetAuthStamps makes the next client of every context use fresh exec credentials.
+func resetAuthStamps() {
+	authStamps.Lock()
+	defer authStamps.Unlock()
+
+	authStamps.m = make(map[string]int64)
+}
+
+// withAuthStamp adds the refresh stamp of a context to its exec plugin environment.
+func withAuthStamp(config *rest.Config, contextName string) {
+	if config.ExecProvider == nil {
+		return
+	}
+	config.ExecProvider.Env = append(config.ExecProvider.Env, clientcmdapi.ExecEnvVar{
+		Name:  authStampEnv,
+		Value: strconv.FormatInt(authStamp(contextName), 10),
+	})
+}
+
+// AuthRefresh is the outcome of refreshing the credentials of a context.
+type AuthRefresh struct {
+	Context       string `json:"context"`
+	Refreshed     bool   `json:"refreshed"`
+	LoginRequired bool   `json:"loginRequired"`
+	Plugin        string `json:"plugin,omitempty"`       // exec plugin command
+	LoginCommand  string `json:"loginCommand,omitempty"` // e.g. "gcloud auth login"
+	Message       string `json:"message,omitempty"`      // what the plugin reported
+}
+
+// AuthRequiredError is returned when a context's credentials can't be refreshed without
+// the user logging in again outside the app.
+type AuthRequiredError struct {
+	Context      string
+	Plugin       string
+	LoginCommand string
+	Message      string
+}
+
+func (e *AuthRequiredError) Error() string {
+	msg := fmt.Sprintf("context '%s' requires a new login", e.Context)
+	if e.LoginCommand != "" {
+		msg += fmt.Sprintf(": run `%s`", e.LoginCommand)
+	}
+	if e.Message != "" {
+		msg += " (" + e.Message + ")"
+	}
+	return msg
+}
+
+// RefreshContextAuth re-runs the exec credential plugin of a context and drops its cached
+// clients, leaving other contexts alone. When the plugin can't get credentials on its own,
+// the result tells which login command the user has to run.
+func (a *App) RefreshContextAuth(contextName string) (*AuthRefresh, error) {
+	return a.refreshContextAuth(contextName)
+}
+
+func (a *App) refreshContextAuth(contextName string) (*AuthRefresh, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		kubeconfigLoadingRules(), &clientcmd.ConfigOverrides{},
+	).RawConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeContext, ok := config.Contexts[contextName]
+	if !ok {
+		return nil, fmt.Errorf("context '%s' not found", contextName)
+	}
+
+	result := &AuthRefresh{Context: contextName}
+	if authInfo := config.AuthInfos[kubeContext.AuthInfo]; authInfo != nil && authInfo.Exec != nil {
+		result.Plugin = authInfo.Exec.Command
+		if err := runExecPlugin(a.ctx, authInfo.Exec, config.Clusters[kubeContext.Cluster]); err != nil {
+			var loginErr *AuthRequiredError
+			if !errors.As(err, &loginErr) {
+				return nil, err
+			}
+			result.LoginRequired = true
+			result.LoginCommand = loginErr.LoginCommand
+			result.Message = loginErr.Message
+			a.logger.Info("context requires a new login", "context", contextName, "plugin", result.Plugin, "message", result.Message)
+			return result, nil
+		}
+	}
+
+	bumpAuthStamp(contextName)
+	a.clients.invalidateContext(contextName)
+	a.capabilities.invalidateContext(contextName)
+	result.Refreshed = true
+	return result, nil
+}
+
+// loginRequired turns a failed refresh into an AuthRequiredError, nil if the refresh worked.
+func (r *AuthRefresh) loginRequired() error {
+	if !r.LoginRequired {
+		return nil
+	}
+	return &AuthRequiredError{Context: r.Context, Plugin: r.Plugin, LoginCommand: r.LoginCommand, Message: r.Message}
+}
+
+// isCredentialError reports whether a request failed on credentials: rejected by the API
+// server, or not obtained from the exec plugin.
+func isCredentialError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var loginErr *AuthRequiredError
+	return apierrors.IsUnauthorized(err) || errors.As(err, &loginErr) || strings.Contains(err.Error(), "getting credentials")
+}
+
+// runExecPlugin runs an exec credential plugin the way client-go does, but non-interactively
+// and capturing its output. A plugin that fails is reported as an AuthRequiredError.
+func runExecPlugin(ctx context.Context, execConfig *clientcmdapi.ExecConfig, cluster *clientcmdapi.Cluster) error {
+	loginErr := &AuthRequiredError{LoginCommand: loginCommand(execConfig, "")}
+	if execConfig.InteractiveMode == clientcmdapi.AlwaysExecInteractiveMode {
+		loginErr.Message = "the credential plugin needs a terminal: run `" + strings.Join(append([]string{execConfig.Command}, execConfig.Args...), " ") + "`"
+		return loginErr
+	}
+
+	spec := map[string]interface{}{"interactive": false}
+	if execConfig.ProvideClusterInfo && cluster != nil {
+		spec["cluster"] = map[string]interface{}{
+			"server":                     cluster.Server,
+			"tls-server-name":            cluster.TLSServerName,
+			"insecure-skip-tls-verify":   cluster.InsecureSkipTLSVerify,
+			"certificate-authority-data": cluster.CertificateAuthorityData,
+			"proxy-url":                  cluster.ProxyURL,
+		}
+	}
+	execInfo, err := json.Marshal(map[string]interface{}{
+		"apiVersion": execConfig.APIVersion,
+		"kind":       "ExecCredential",
+		"spec":       spec,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, execPluginTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, execConfig.Command, execConfig.Args...)
+	cmd.Env = os.Environ()
+	for _, env := range execConfig.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	cmd.Env = append(cmd.Env, "KUBERNETES_EXEC_INFO="+string(execInfo))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		loginErr.LoginCommand = ""
+		loginErr.Message = fmt.Sprintf("credential plugin %s not found", execConfig.Command)
+		if execConfig.InstallHint != "" {
+			loginErr.Message += ": " + execConfig.InstallHint
+		}
+		return loginErr
+	case ctx.Err() != nil:
+		loginErr.Message = fmt.Sprintf("credential plugin %s timed out after %s, it may be waiting for a login", execConfig.Command, execPluginTimeout)
+		return loginErr
+	case err != nil:
+		loginErr.LoginCommand = loginCommand(execConfig, stderr.String())
+		loginErr.Message = pluginOutput(stderr.String())
+		if loginErr.Message == "" {
+			loginErr.Message = err.Error()
+		}
+		return loginErr
+	}
+
+	var credential struct {
+		Status *struct {
+			Token                 string `json:"token"`
+			ClientCertificateData string `json:"clientCertificateData"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil {
+		return fmt.Errorf("invalid output of credential plugin %s: %w", execConfig.Command, err)
+	}
+	if credential.Status == nil || (credential.Status.Token == "" && credential.Status.ClientCertificateData == "") {
+		loginErr.Message = fmt.Sprintf("credential plugin %s returned no credentials", execConfig.Command)
+		return loginErr
+	}
+	return nil
+}
+
+// loginCommand guesses the command that logs the user in for well-known credential plugins.
+func loginCommand(execConfig *clientcmdapi.ExecConfig, stderr string) string {
+	args := strings.Join(execConfig.Args, " ")
+	switch filepath.Base(execConfig.Command) {
+	case "gke-gcloud-auth-plugin", "gcloud":
+		return "gcloud auth login"
+	case "aws", "aws-iam-authenticator":
+		if strings.Contains(strings.ToLower(stderr), "sso") {
+			return "aws sso login"
+		}
+		return "aws configure"
+	case "kubelogin":
+		if strings.Contains(args, "azurecli") {
+			return "az login"
+		}
+		return "kubelogin get-token " + args
+	case "kubectl":
+		if strings.Contains(args, "oidc-login") {
+			return "kubectl " + args
+		}
+	case "doctl":
+		return "doctl auth init"
+	}
+	return ""
+}
+
+// pluginOutput trims plugin stderr down to its last lines.
+func pluginOutput(stderr string) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	if len(lines) > 5 {
+		lines = lines[len(lines)-5:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestIndexFile lists the files a manifest export wrote to a directory. Only files listed
+// there are removed by a later export to the same directory.
+const manifestIndexFile = ".tinysystems-export.json"
+
+// manifestIndex is the content of manifestIndexFile.
+type manifestIndex struct {
+	Files []string `json:"files"`
+}
+
+// clusterMetadataFields are set by the cluster and must not be applied to another one.
+// The namespace is left out too, so kubectl apply -n or Kustomize can set it.
+var clusterMetadataFields = []string{
+	"namespace", "uid", "resourceVersion", "generation", "creationTimestamp",
+	"deletionTimestamp", "deletionGracePeriodSeconds", "managedFields", "ownerReferences",
+	"finalizers", "selfLink",
+}
+
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// exportManifest is a resource manifest and its file within a manifest directory.
+type exportManifest struct {
+	path   string
+	object map[string]interface{}
+}
+
+// ConvertExportToYAML renders JSON export data, as returned by ExportProject, as YAML.
+func (a *App) ConvertExportToYAML(jsonData string) (string, error) {
+	data, err := yaml.JSONToYAML([]byte(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("unable to convert export to YAML: %w", err)
+	}
+	return string(data), nil
+}
+
+// ExportProjectManifests exports the TinyProject, TinyFlow, TinyNode, TinyWidgetPage and TinyScenario
+// resources of a project (or of the selection, if given) as multi-document YAML for kubectl apply.
+func (a *App) ExportProjectManifests(contextName string, namespace string, projectName string, selection *ExportSelection) (string, error) {
+	manifests, _, err := a.buildProjectManifests(contextName, namespace, projectName, selection, nil)
+	if err != nil {
+		return "", err
+	}
+	return renderManifests(manifests)
+}
+
+// ExportProjectManifestsRedacted is ExportProjectManifests with secrets in port and edge
+// configurations replaced by ${NAME} placeholders, like ExportProjectRedacted.
+func (a *App) ExportProjectManifestsRedacted(contextName string, namespace string, projectName string, selection *ExportSelection, options RedactionOptions) (*RedactedExport, error) {
+	manifests, report, err := a.buildProjectManifests(contextName, namespace, projectName, selection, &options)
+	if err != nil {
+		return nil, err
+	}
+	data, err := renderManifests(manifests)
+	if err != nil {
+		return nil, err
+	}
+	return &RedactedExport{Data: data, Report: *report}, nil
+}
+
+// renderManifests renders manifests as multi-document YAML.
+func renderManifests(manifests []exportManifest) (string, error) {
+	var b strings.Builder
+	for i, m := range manifests {
+		data, err := yaml.Marshal(m.object)
+		if err != nil {
+			return "", fmt.Errorf("unable to render %s: %w", m.path, err)
+		}
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		b.Write(data)
+	}
+	return b.String(), nil
+}
+
+// ExportProjectManifestsToDirectory asks for a directory and writes the project resources to it, one
+// file per resource, with a kustomization.yaml listing them. The directory must be empty or have
+// been exported to before; files of resources removed from the project since that export are deleted. Secrets are replaced by
+// placeholders when redaction options are given.
+func (a *App) ExportProjectManifestsToDirectory(contextName string, namespace string, projectName string, redaction *RedactionOptions) (string, error) {
+	manifests, _, err := a.buildProjectManifests(contextName, namespace, projectName, nil, redaction)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:                "Select export directory",
+		CanCreateDirectories: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	if dir == "" {
+		// User cancelled
+		return "", nil
+	}
+
+	if err := writeManifestDirectory(dir, manifests); err != nil {
+		return "", err
+	}
+	a.logger.Info("exported project manifests", "project", projectName, "dir", dir, "resources", len(manifests))
+	return dir, nil
+}
+
+// buildProjectManifests collects the project resources, stripped of status and cluster-specific fields.
+// With redaction options, secrets in node configurations are replaced and the report is returned.
+func (a *App) buildProjectManifests(contextName string, namespace string, projectName string, selection *ExportSelection, redaction *RedactionOptions) ([]exportManifest, *RedactionReport, error) {
+	mgr, err := a.getManager(contextName, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	project, err := mgr.GetProject(a.ctx, projectName, namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get project: %w", err)
+	}
+
+	flows, err := mgr.GetFlowList(a.ctx, projectName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get flows: %w", err)
+	}
+
+	allNodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get nodes: %w", err)
+	}
+	nodes := make(map[string]v1alpha1.TinyNode, len(allNodes))
+	for _, node := range allNodes {
+		nodes[node.Name] = node
+	}
+
+	pages, err := mgr.GetProjectPageWidgets(a.ctx, projectName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get widget pages: %w", err)
+	}
+
+	// don't fail if scenarios can't be loaded
+	scenarios, _ := mgr.GetProjectScenarios(a.ctx, projectName)
+
+	if selection != nil {
+		flows, nodes, pages, err = selectExport(*selection, flows, nodes, pages)
+		if err != nil {
+			return nil, nil, err
+		}
+		var selected []v1alpha1.TinyScenario
+		for _, s := range scenarios {
+			complete := len(s.Spec.Ports) > 0
+			for _, p := range s.Spec.Ports {
+				if _, ok := nodes[portNodeID(p.Port)]; !ok {
+					complete = false
+					break
+				}
+			}
+			if complete {
+				selected = append(selected, s)
+			}
+		}
+		scenarios = selected
+	}
+
+	var report *RedactionReport
+	if redaction != nil {
+		r, err := redactNodePorts(nodes, *redaction)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to redact secrets: %w", err)
+		}
+		report = &r
+	}
+
+	var manifests []exportManifest
+	add := func(path, kind string, obj interface{}) error {
+		m, err := toManifest(kind, obj)
+		if err != nil {
+			return fmt.Errorf("unable to convert %s: %w", path, err)
+		}
+		manifests = append(manifests, exportManifest{path: path, object: m})
+		return nil
+	}
+
+	if err := add("project.yaml", "TinyProject", project); err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(flows, func(i, j int) bool { return flows[i].Name < flows[j].Name })
+	for _, flow := range flows {
+		if err := add("flows/"+flow.Name+".yaml", "TinyFlow", flow); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	nodeNames := make([]string, 0, len(nodes))
+	for name := range nodes {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+	for _, name := range nodeNames {
+		node := nodes[name]
+		// Like ExportProject, leave out _control port configuration — it holds runtime state
+		ports := make([]v1alpha1.TinyNodePortConfig, 0, len(node.Spec.Ports))
+		for _, p := range node.Spec.Ports {
+			if p.Port == v1alpha1.ControlPort && p.From == "" {
+				continue
+			}
+			ports = append(ports, p)
+		}
+		node.Spec.Ports = ports
+		if err := add("nodes/"+name+".yaml", "TinyNode", node); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Name < pages[j].Name })
+	for _, page := range pages {
+		if err := add("pages/"+page.Name+".yaml", "TinyWidgetPage", page); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sort.Slice(scenarios, func(i, j int) bool { return scenarios[i].Name < scenarios[j].Name })
+	for _, scenario := range scenarios {
+		if err := add("scenarios/"+scenario.Name+".yaml", "TinyScenario", scenario); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return manifests, report, nil
+}
+
+// toManifest returns obj as an unstructured manifest of kind without status and cluster-specific metadata.
+func toManifest(kind string, obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	// Listed objects come without type information
+	m["apiVersion"] = v1alpha1.GroupVersion.String()
+	m["kind"] = kind
+	delete(m, "status")
+
+	if meta, ok := m["metadata"].(map[string]interface{}); ok {
+		for _, field := range clusterMetadataFields {
+			delete(meta, field)
+		}
+		if annotations, ok := meta["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedAnnotation)
+			if len(annotations) == 0 {
+				delete(meta, "annotations")
+			}
+		}
+	}
+	return m, nil
+}
+
+// writeManifestDirectory writes manifests and a kustomization.yaml into dir and removes the
+// files an earlier export wrote that aren't written again. A directory that isn't empty and has
+// no export index is refused, so unrelated manifests are never overwritten or removed.
+func writeManifestDirectory(dir string, manifests []exportManifest) error {
+	previous, err := readManifestIndex(dir)
+	if err != nil {
+		return err
+	}
+
+	resources := make([]string, 0, len(manifests))
+	written := make(map[string]bool, len(manifests)+1)
+	for _, m := range manifests {
+		data, err := yaml.Marshal(m.object)
+		if err != nil {
+			return fmt.Errorf("unable to render %s: %w", m.path, err)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(m.path))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return err
+		}
+		resources = append(resources, m.path)
+		written[m.path] = true
+	}
+
+	kustomization, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  resources,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "kustomization.yaml"), kustomization, 0600); err != nil {
+		return err
+	}
+	written["kustomization.yaml"] = true
+
+	for _, file := range previous.Files {
+		if written[file] || !isManifestPath(file) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(file))); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove stale manifest: %w", err)
+		}
+	}
+
+	index, err := json.MarshalIndent(manifestIndex{Files: append(resources, "kustomization.yaml")}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, manifestIndexFile), index, 0600)
+}
+
+// readManifestIndex returns the files an earlier export wrote to dir. It fails when dir holds
+// other files but no index.
+func readManifestIndex(dir string) (manifestIndex, error) {
+	var index manifestIndex
+	data, err := os.ReadFile(filepath.Join(dir, manifestIndexFile))
+	if err == nil {
+		if err := json.Unmarshal(data, &index); err != nil {
+			return index, fmt.Errorf("invalid export index %s: %w", manifestIndexFile, err)
+		}
+		return index, nil
+	}
+	if !os.IsNotExist(err) {
+		return index, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return index, err
+	}
+	if len(entries) > 0 {
+		return index, fmt.Errorf("directory %s is not empty and wasn't exported to before: choose an empty directory", dir)
+	}
+	return index, nil
+}
+
+// isManifestPath reports whether an index entry is a file an export writes, so a tampered
+// index can't remove anything else.
+func isManifestPath(file string) bool {
+	if file == "kustomization.yaml" {
+		return true
+	}
+	dir, name := path.Split(file)
+	switch dir {
+	case "flows/", "nodes/", "pages/", "scenarios/":
+		return name != "" && path.Ext(name) == ".yaml" && !strings.Contains(name, "..")
+	}
+	return false
+}
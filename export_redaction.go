@@ -0,0 +1,410 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/utils"
+)
+
+const (
+	redactReasonSchema  = "schema"
+	redactReasonKeyName = "key"
+	redactReasonEntropy = "entropy"
+
+	defaultRedactMinLength = 20
+	defaultRedactEntropy   = 4.0
+)
+
+// defaultSecretKeyNames are matched against configuration keys, ignoring case, "_" and "-".
+var defaultSecretKeyNames = []string{
+	"password", "passwd", "secret", "token", "apikey", "accesskey", "privatekey",
+	"credential", "authorization", "bearer", "clientsecret", "sessionkey",
+}
+
+// placeholderPattern matches ${NAME} placeholders, values that are already redacted.
+var placeholderPattern = regexp.MustCompile(`^\$\{[A-Za-z_][A-Za-z0-9_]*\}$`)
+
+// RedactionOptions configures which configuration values an export replaces with placeholders.
+type RedactionOptions struct {
+	SchemaSecrets    bool     `json:"schemaSecrets"`    // fields the port schema marks as secret (format password, writeOnly, secret)
+	KeyNames         bool     `json:"keyNames"`         // well-known secret key names like password or token
+	ExtraKeyNames    []string `json:"extraKeyNames"`    // additional key names treated as secrets
+	HighEntropy      bool     `json:"highEntropy"`      // long random-looking strings
+	MinEntropyLength int      `json:"minEntropyLength"` // shortest string checked for entropy, 20 if unset
+	EntropyThreshold float64  `json:"entropyThreshold"` // bits per character, 4.0 if unset
+}
+
+// Redaction is a single value replaced by a placeholder. The value itself is never reported.
+type Redaction struct {
+	Placeholder string `json:"placeholder"`
+	Node        string `json:"node,omitempty"`
+	Edge        string `json:"edge,omitempty"`
+	Port        string `json:"port,omitempty"`
+	Path        string `json:"path"`
+	Reason      string `json:"reason"` // schema, key or entropy
+}
+
+// RedactionReport lists everything an export redacted.
+type RedactionReport struct {
+	Redactions   []Redaction `json:"redactions"`
+	Placeholders []string    `json:"placeholders"`
+}
+
+// RedactedExport is a project export with secrets replaced by placeholders.
+type RedactedExport struct {
+	Data   string          `json:"data"`
+	Report RedactionReport `json:"report"`
+}
+
+// ExportProjectRedacted exports a project (or the selection, if given) with secrets in port and
+// edge configurations replaced by ${NAME} placeholders, and reports what was replaced.
+func (a *App) ExportProjectRedacted(contextName string, namespace string, projectName string, selection *ExportSelection, options RedactionOptions) (*RedactedExport, error) {
+	export, err := a.buildProjectExport(contextName, namespace, projectName, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	report := redactExport(export, options)
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &RedactedExport{Data: string(data), Report: report}, nil
+}
+
+// redactExport replaces secrets in handle and edge configurations of the export in place.
+func redactExport(export *utils.ProjectExport, options RedactionOptions) RedactionReport {
+	r := newRedactor(options)
+
+	// Placeholders are named after node labels
+	labels := make(map[string]string)
+	for _, elem := range export.Elements {
+		if !isNodeElement(elem) {
+			continue
+		}
+		id, _ := elem["id"].(string)
+		data, _ := elem["data"].(map[string]interface{})
+		label, _ := data["label"].(string)
+		if label == "" {
+			label = id
+		}
+		labels[id] = label
+	}
+
+	for _, elem := range export.Elements {
+		data, _ := elem["data"].(map[string]interface{})
+		if data == nil {
+			continue
+		}
+		id, _ := elem["id"].(string)
+
+		if !isNodeElement(elem) {
+			if config, ok := data["configuration"]; ok {
+				schema, _ := data["schema"].(map[string]interface{})
+				targetHandle, _ := elem["targetHandle"].(string)
+				loc := Redaction{Edge: id, Port: targetHandle}
+				target, _ := elem["target"].(string)
+				data["configuration"] = r.walk(config, schema, schema, "", loc, labels[target]+"_"+targetHandle)
+			}
+			continue
+		}
+
+		handles, _ := data["handles"].([]interface{})
+		for _, h := range handles {
+			handle, ok := h.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			config, ok := handle["configuration"]
+			if !ok {
+				continue
+			}
+			portID, _ := handle["id"].(string)
+			schema, _ := handle["schema"].(map[string]interface{})
+			loc := Redaction{Node: id, Port: portID}
+			handle["configuration"] = r.walk(config, schema, schema, "", loc, labels[id])
+		}
+	}
+
+	return r.report()
+}
+
+// redactNodePorts replaces secrets in the port and edge configurations of nodes in place,
+// with the same detectors and placeholder names as redactExport.
+func redactNodePorts(nodes map[string]v1alpha1.TinyNode, options RedactionOptions) (RedactionReport, error) {
+	r := newRedactor(options)
+
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		node := nodes[name]
+		ports := make([]v1alpha1.TinyNodePortConfig, len(node.Spec.Ports))
+		copy(ports, node.Spec.Ports)
+		for i, p := range ports {
+			if len(p.Configuration) == 0 {
+				continue
+			}
+			var config interface{}
+			if err := json.Unmarshal(p.Configuration, &config); err != nil {
+				return RedactionReport{}, fmt.Errorf("invalid configuration of port %s of node %s: %w", p.Port, name, err)
+			}
+			var schema map[string]interface{}
+			_ = json.Unmarshal(p.Schema, &schema)
+
+			// Edge configurations are stored on the target node, named like in redactExport
+			prefix := nodeTitle(&node)
+			if p.From != "" {
+				prefix += "_" + p.Port
+			}
+			config = r.walk(config, schema, schema, "", Redaction{Node: name, Port: p.Port}, prefix)
+
+			data, err := json.Marshal(config)
+			if err != nil {
+				return RedactionReport{}, err
+			}
+			ports[i].Configuration = data
+		}
+		node.Spec.Ports = ports
+		nodes[name] = node
+	}
+
+	return r.report(), nil
+}
+
+type redactor struct {
+	options    RedactionOptions
+	keyNames   []string
+	redactions []Redaction
+	byValue    map[string]string // secret value -> placeholder, same secrets share a placeholder
+	used       map[string]bool
+}
+
+func newRedactor(options RedactionOptions) *redactor {
+	if options.MinEntropyLength <= 0 {
+		options.MinEntropyLength = defaultRedactMinLength
+	}
+	if options.EntropyThreshold <= 0 {
+		options.EntropyThreshold = defaultRedactEntropy
+	}
+	r := &redactor{
+		options: options,
+		byValue: make(map[string]string),
+		used:    make(map[string]bool),
+	}
+	if options.KeyNames {
+		r.keyNames = append(r.keyNames, defaultSecretKeyNames...)
+	}
+	for _, k := range options.ExtraKeyNames {
+		if k = normalizeKeyName(k); k != "" {
+			r.keyNames = append(r.keyNames, k)
+		}
+	}
+	return r
+}
+
+// walk returns value with secrets replaced. schema is the JSON schema of value, root the
+// schema $ref are resolved against, path the dotted location of value within the configuration.
+func (r *redactor) walk(value interface{}, schema, root map[string]interface{}, path string, loc Redaction, prefix string) interface{} {
+	schema = resolveSchemaRef(schema, root)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props, _ := schema["properties"].(map[string]interface{})
+		// Sorted, so placeholder names are stable between exports
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childSchema, _ := props[key].(map[string]interface{})
+			v[key] = r.walkKey(v[key], childSchema, root, joinPath(path, key), key, loc, prefix)
+		}
+		return v
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, child := range v {
+			v[i] = r.walk(child, items, root, fmt.Sprintf("%s[%d]", path, i), loc, prefix)
+		}
+		return v
+	case string:
+		if reason := r.check(v, schema, ""); reason != "" {
+			return r.redact(v, path, reason, loc, prefix)
+		}
+	}
+	return value
+}
+
+// walkKey is walk for a value stored under key, so key names can mark it as secret.
+func (r *redactor) walkKey(value interface{}, schema, root map[string]interface{}, path, key string, loc Redaction, prefix string) interface{} {
+	if s, ok := value.(string); ok {
+		if reason := r.check(s, resolveSchemaRef(schema, root), key); reason != "" {
+			return r.redact(s, path, reason, loc, prefix+"_"+key)
+		}
+		return value
+	}
+	return r.walk(value, schema, root, path, loc, prefix)
+}
+
+// check returns why a string value is a secret, or "" if it isn't.
+func (r *redactor) check(value string, schema map[string]interface{}, key string) string {
+	if value == "" || placeholderPattern.MatchString(value) || strings.Contains(value, "{{") {
+		// Empty, already redacted, or a mapping expression
+		return ""
+	}
+	if r.options.SchemaSecrets && isSecretSchema(schema) {
+		return redactReasonSchema
+	}
+	if key != "" && r.isSecretKey(key) {
+		return redactReasonKeyName
+	}
+	if r.options.HighEntropy && len(value) >= r.options.MinEntropyLength && !strings.ContainsAny(value, " \n\t") &&
+		shannonEntropy(value) >= r.options.EntropyThreshold {
+		return redactReasonEntropy
+	}
+	return ""
+}
+
+func (r *redactor) isSecretKey(key string) bool {
+	key = normalizeKeyName(key)
+	for _, name := range r.keyNames {
+		if strings.Contains(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact records a redaction and returns the placeholder for value.
+func (r *redactor) redact(value, path, reason string, loc Redaction, name string) string {
+	placeholder, ok := r.byValue[value]
+	if !ok {
+		base := placeholderName(name)
+		candidate := base
+		for i := 2; r.used[candidate]; i++ {
+			candidate = fmt.Sprintf("%s_%d", base, i)
+		}
+		r.used[candidate] = true
+		placeholder = "${" + candidate + "}"
+		r.byValue[value] = placeholder
+	}
+
+	loc.Placeholder = placeholder
+	loc.Path = path
+	loc.Reason = reason
+	r.redactions = append(r.redactions, loc)
+	return placeholder
+}
+
+func (r *redactor) report() RedactionReport {
+	report := RedactionReport{
+		Redactions:   r.redactions,
+		Placeholders: make([]string, 0, len(r.byValue)),
+	}
+	if report.Redactions == nil {
+		report.Redactions = []Redaction{}
+	}
+	for _, p := range r.byValue {
+		report.Placeholders = append(report.Placeholders, p)
+	}
+	sort.Strings(report.Placeholders)
+	sort.SliceStable(report.Redactions, func(i, j int) bool {
+		return report.Redactions[i].Placeholder < report.Redactions[j].Placeholder
+	})
+	return report
+}
+
+// isSecretSchema reports whether a JSON schema marks its value as secret.
+func isSecretSchema(schema map[string]interface{}) bool {
+	if schema == nil {
+		return false
+	}
+	if format, _ := schema["format"].(string); format == "password" {
+		return true
+	}
+	if secret, _ := schema["secret"].(bool); secret {
+		return true
+	}
+	writeOnly, _ := schema["writeOnly"].(bool)
+	return writeOnly
+}
+
+// resolveSchemaRef follows a local "#/$defs/X" or "#/definitions/X" reference.
+func resolveSchemaRef(schema, root map[string]interface{}) map[string]interface{} {
+	for i := 0; schema != nil && i < 10; i++ {
+		ref, _ := schema["$ref"].(string)
+		if ref == "" || root == nil {
+			return schema
+		}
+		var defs map[string]interface{}
+		var name string
+		switch {
+		case strings.HasPrefix(ref, "#/$defs/"):
+			defs, _ = root["$defs"].(map[string]interface{})
+			name = strings.TrimPrefix(ref, "#/$defs/")
+		case strings.HasPrefix(ref, "#/definitions/"):
+			defs, _ = root["definitions"].(map[string]interface{})
+			name = strings.TrimPrefix(ref, "#/definitions/")
+		default:
+			return schema
+		}
+		next, _ := defs[name].(map[string]interface{})
+		if next == nil {
+			return schema
+		}
+		schema = next
+	}
+	return schema
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, c := range s {
+		counts[c]++
+		n++
+	}
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(n)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func normalizeKeyName(key string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(key))
+}
+
+var placeholderInvalidChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// placeholderName turns a node label and key into an upper snake case variable name.
+func placeholderName(name string) string {
+	name = strings.Trim(placeholderInvalidChars.ReplaceAllString(strings.ToUpper(name), "_"), "_")
+	if name == "" {
+		return "SECRET"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "SECRET_" + name
+	}
+	return name
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
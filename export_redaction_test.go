@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/tiny-systems/module/pkg/utils"
+)
+
+func TestPlaceholderName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Http Client_password", "HTTP_CLIENT_PASSWORD"},
+		{"api-key", "API_KEY"},
+		{"  spaced  out  ", "SPACED_OUT"},
+		{"1st node_token", "SECRET_1ST_NODE_TOKEN"},
+		{"", "SECRET"},
+		{"--", "SECRET"},
+		{"über_token", "BER_TOKEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := placeholderName(tt.name); got != tt.want {
+				t.Errorf("placeholderName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShannonEntropy(t *testing.T) {
+	tests := []struct {
+		s    string
+		want float64
+	}{
+		{"", 0},
+		{"aaaa", 0},
+		{"abab", 1},
+		{"abcd", 2},
+		{"0123456789abcdef", 4},
+		{"aab", 0.9183},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := shannonEntropy(tt.s); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("shannonEntropy(%q) = %.4f, want %.4f", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactExport(t *testing.T) {
+	const randomToken = "x7Kq9Lm2Vb8Np4Rs6Tw1Yz3Hc5Jd0Fg"
+
+	tests := []struct {
+		name       string
+		options    RedactionOptions
+		config     string // configuration of the node's settings handle
+		schema     string
+		edgeConfig string // configuration of the edge into the node
+		wantConfig string
+		wantEdge   string
+		// placeholder, path and reason of every redaction
+		wantRedactions [][3]string
+	}{
+		{
+			name:           "nothing enabled",
+			config:         `{"password":"hunter2"}`,
+			wantConfig:     `{"password":"hunter2"}`,
+			wantRedactions: [][3]string{},
+		},
+		{
+			name:           "schema marks secrets",
+			options:        RedactionOptions{SchemaSecrets: true},
+			config:         `{"pass":"hunter2","user":"bob","key":"k1","hidden":"h1"}`,
+			schema:         `{"properties":{"pass":{"type":"string","format":"password"},"key":{"writeOnly":true},"hidden":{"secret":true}}}`,
+			wantConfig:     `{"hidden":"${HTTP_CLIENT_HIDDEN}","key":"${HTTP_CLIENT_KEY}","pass":"${HTTP_CLIENT_PASS}","user":"bob"}`,
+			wantRedactions: [][3]string{{"${HTTP_CLIENT_HIDDEN}", "hidden", "schema"}, {"${HTTP_CLIENT_KEY}", "key", "schema"}, {"${HTTP_CLIENT_PASS}", "pass", "schema"}},
+		},
+		{
+			name:           "schema refs are resolved",
+			options:        RedactionOptions{SchemaSecrets: true},
+			config:         `{"auth":{"secret":"s1"}}`,
+			schema:         `{"properties":{"auth":{"$ref":"#/$defs/Auth"}},"$defs":{"Auth":{"properties":{"secret":{"format":"password"}}}}}`,
+			wantConfig:     `{"auth":{"secret":"${HTTP_CLIENT_SECRET}"}}`,
+			wantRedactions: [][3]string{{"${HTTP_CLIENT_SECRET}", "auth.secret", "schema"}},
+		},
+		{
+			name:           "well-known and extra key names",
+			options:        RedactionOptions{KeyNames: true, ExtraKeyNames: []string{"Host-Name"}},
+			config:         `{"apiToken":"abc","name":"x","hostname":"db.local","headers":[{"Authorization":"Bearer abc"}]}`,
+			wantConfig:     `{"apiToken":"${HTTP_CLIENT_APITOKEN}","headers":[{"Authorization":"${HTTP_CLIENT_AUTHORIZATION}"}],"hostname":"${HTTP_CLIENT_HOSTNAME}","name":"x"}`,
+			wantRedactions: [][3]string{{"${HTTP_CLIENT_APITOKEN}", "apiToken", "key"}, {"${HTTP_CLIENT_AUTHORIZATION}", "headers[0].Authorization", "key"}, {"${HTTP_CLIENT_HOSTNAME}", "hostname", "key"}},
+		},
+		{
+			name:           "high entropy strings",
+			options:        RedactionOptions{HighEntropy: true},
+			config:         `{"value":"` + randomToken + `","text":"just some words that are long enough"}`,
+			wantConfig:     `{"text":"just some words that are long enough","value":"${HTTP_CLIENT_VALUE}"}`,
+			wantRedactions: [][3]string{{"${HTTP_CLIENT_VALUE}", "value", "entropy"}},
+		},
+		{
+			name:           "placeholders and mapping expressions are kept",
+			options:        RedactionOptions{KeyNames: true, HighEntropy: true},
+			config:         `{"password":"${DB_PASSWORD}","token":"{{$.request.token}}"}`,
+			wantConfig:     `{"password":"${DB_PASSWORD}","token":"{{$.request.token}}"}`,
+			wantRedactions: [][3]string{},
+		},
+		{
+			name:           "same secret shares a placeholder across node and edge",
+			options:        RedactionOptions{KeyNames: true},
+			config:         `{"password":"hunter2","token":"other"}`,
+			edgeConfig:     `{"password":"hunter2"}`,
+			wantConfig:     `{"password":"${HTTP_CLIENT_PASSWORD}","token":"${HTTP_CLIENT_TOKEN}"}`,
+			wantEdge:       `{"password":"${HTTP_CLIENT_PASSWORD}"}`,
+			wantRedactions: [][3]string{{"${HTTP_CLIENT_PASSWORD}", "password", "key"}, {"${HTTP_CLIENT_PASSWORD}", "password", "key"}, {"${HTTP_CLIENT_TOKEN}", "token", "key"}},
+		},
+		{
+			name:           "different secrets under the same name are numbered",
+			options:        RedactionOptions{KeyNames: true},
+			config:         `{"password":"one"}`,
+			edgeConfig:     `{"password":"two"}`,
+			wantConfig:     `{"password":"${HTTP_CLIENT_PASSWORD}"}`,
+			wantEdge:       `{"password":"${HTTP_CLIENT_REQUEST_PASSWORD}"}`,
+			wantRedactions: [][3]string{{"${HTTP_CLIENT_PASSWORD}", "password", "key"}, {"${HTTP_CLIENT_REQUEST_PASSWORD}", "password", "key"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handle := map[string]interface{}{"id": "settings", "configuration": parseTestJSON(t, tt.config)}
+			if tt.schema != "" {
+				handle["schema"] = parseTestJSON(t, tt.schema)
+			}
+			node := map[string]interface{}{
+				"id":   "n1",
+				"type": "tinyNode",
+				"data": map[string]interface{}{"label": "Http Client", "handles": []interface{}{handle}},
+			}
+			export := &utils.ProjectExport{Elements: []map[string]interface{}{node}}
+
+			var edgeData map[string]interface{}
+			if tt.edgeConfig != "" {
+				edgeData = map[string]interface{}{"configuration": parseTestJSON(t, tt.edgeConfig)}
+				export.Elements = append(export.Elements, map[string]interface{}{
+					"id":           "e1",
+					"type":         "tinyEdge",
+					"source":       "n0",
+					"target":       "n1",
+					"targetHandle": "request",
+					"data":         edgeData,
+				})
+			}
+
+			report := redactExport(export, tt.options)
+
+			if got := testJSONString(t, handle["configuration"]); got != tt.wantConfig {
+				t.Errorf("configuration = %s, want %s", got, tt.wantConfig)
+			}
+			if edgeData != nil {
+				if got := testJSONString(t, edgeData["configuration"]); got != tt.wantEdge {
+					t.Errorf("edge configuration = %s, want %s", got, tt.wantEdge)
+				}
+			}
+
+			got := make([][3]string, 0, len(report.Redactions))
+			for _, r := range report.Redactions {
+				got = append(got, [3]string{r.Placeholder, r.Path, r.Reason})
+			}
+			if !reflect.DeepEqual(got, tt.wantRedactions) {
+				t.Errorf("redactions = %v, want %v", got, tt.wantRedactions)
+			}
+		})
+	}
+}
+
+func parseTestJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("invalid test JSON %s: %v", s, err)
+	}
+	return v
+}
+
+func testJSONString(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
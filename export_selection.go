@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/utils"
+)
+
+// ExportSelection picks flows (by resource name) and dashboard pages (by resource name or title) to export.
+type ExportSelection struct {
+	Flows []string `json:"flows"`
+	Pages []string `json:"pages"`
+}
+
+// ExportProjectSelection exports only the selected flows and pages, together with the nodes of
+// other flows they reference, as a project export that ImportProject accepts.
+func (a *App) ExportProjectSelection(contextName string, namespace string, projectName string, selection ExportSelection) (string, error) {
+	return a.exportProject(contextName, namespace, projectName, &selection)
+}
+
+// selectExport reduces flows, nodes and pages to the selection. Besides the nodes of selected
+// flows it keeps nodes shared with them, nodes connected to them by edges and nodes referenced
+// by widgets of selected pages; the flows of those nodes are kept so the export can be imported.
+// Kept nodes only carry edges and edge configurations of selected flows.
+func selectExport(sel ExportSelection, flows []v1alpha1.TinyFlow, nodes map[string]v1alpha1.TinyNode, pages []v1alpha1.TinyWidgetPage) ([]v1alpha1.TinyFlow, map[string]v1alpha1.TinyNode, []v1alpha1.TinyWidgetPage, error) {
+	if len(sel.Flows) == 0 && len(sel.Pages) == 0 {
+		return nil, nil, nil, fmt.Errorf("nothing selected to export")
+	}
+
+	var notFound []string
+
+	existingFlows := make(map[string]bool, len(flows))
+	for _, f := range flows {
+		existingFlows[f.Name] = true
+	}
+	selectedFlows := make(map[string]bool)
+	for _, name := range sel.Flows {
+		if !existingFlows[name] {
+			notFound = append(notFound, fmt.Sprintf("flow %q", name))
+			continue
+		}
+		selectedFlows[name] = true
+	}
+
+	var selectedPages []v1alpha1.TinyWidgetPage
+	for _, name := range sel.Pages {
+		found := false
+		for _, p := range pages {
+			if p.Name == name || p.Annotations[v1alpha1.PageTitleAnnotation] == name {
+				selectedPages = append(selectedPages, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			notFound = append(notFound, fmt.Sprintf("page %q", name))
+		}
+	}
+
+	if len(notFound) > 0 {
+		return nil, nil, nil, fmt.Errorf("not found in project: %s", strings.Join(notFound, ", "))
+	}
+
+	keep := make(map[string]v1alpha1.TinyNode)
+	add := func(name string) {
+		if node, ok := nodes[name]; ok {
+			keep[name] = node
+		}
+	}
+
+	for name, node := range nodes {
+		if selectedFlows[node.Labels[v1alpha1.FlowNameLabel]] {
+			add(name)
+			continue
+		}
+		for flow := range selectedFlows {
+			if containsFlow(node.Annotations[v1alpha1.SharedWithFlowsAnnotation], flow) {
+				add(name)
+				break
+			}
+		}
+	}
+
+	// Edges of selected flows may cross into other flows, in both directions
+	for name, node := range nodes {
+		for _, edge := range node.Spec.Edges {
+			if !selectedFlows[edgeFlow(edge.FlowID, node)] {
+				continue
+			}
+			add(name)
+			add(portNodeID(edge.To))
+		}
+	}
+
+	for _, page := range selectedPages {
+		for _, w := range page.Spec.Widgets {
+			add(portNodeID(w.Port))
+		}
+	}
+
+	keepFlows := make(map[string]bool)
+	for name, node := range keep {
+		keepFlows[node.Labels[v1alpha1.FlowNameLabel]] = true
+		keep[name] = withSelectedFlowEdges(node, selectedFlows)
+	}
+	var resultFlows []v1alpha1.TinyFlow
+	for _, f := range flows {
+		if keepFlows[f.Name] {
+			resultFlows = append(resultFlows, f)
+		}
+	}
+
+	return resultFlows, keep, selectedPages, nil
+}
+
+// withSelectedFlowEdges returns node without the edges and edge configurations of flows
+// that aren't selected. Node settings are kept.
+func withSelectedFlowEdges(node v1alpha1.TinyNode, selectedFlows map[string]bool) v1alpha1.TinyNode {
+	edges := make([]v1alpha1.TinyNodeEdge, 0, len(node.Spec.Edges))
+	for _, edge := range node.Spec.Edges {
+		if selectedFlows[edgeFlow(edge.FlowID, node)] {
+			edges = append(edges, edge)
+		}
+	}
+	ports := make([]v1alpha1.TinyNodePortConfig, 0, len(node.Spec.Ports))
+	for _, pc := range node.Spec.Ports {
+		if pc.From == "" || selectedFlows[edgeFlow(pc.FlowID, node)] {
+			ports = append(ports, pc)
+		}
+	}
+	node.Spec.Edges = edges
+	node.Spec.Ports = ports
+	return node
+}
+
+// edgeFlow returns the flow of an edge or edge configuration; older ones without a flow ID
+// belong to the flow of the node holding them.
+func edgeFlow(flowID string, node v1alpha1.TinyNode) string {
+	if flowID == "" {
+		return node.Labels[v1alpha1.FlowNameLabel]
+	}
+	return flowID
+}
+
+// filterExportElements drops edges whose source or target node isn't exported.
+func filterExportElements(elements []map[string]interface{}, nodes map[string]v1alpha1.TinyNode) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(elements))
+	for _, elem := range elements {
+		if !isNodeElement(elem) {
+			source, _ := elem["source"].(string)
+			target, _ := elem["target"].(string)
+			if _, ok := nodes[source]; !ok {
+				continue
+			}
+			if _, ok := nodes[target]; !ok {
+				continue
+			}
+		}
+		result = append(result, elem)
+	}
+	return result
+}
+
+// filterExportScenarios keeps scenarios whose ports all belong to exported nodes.
+func filterExportScenarios(scenarios []utils.ExportScenario, nodes map[string]v1alpha1.TinyNode) []utils.ExportScenario {
+	var result []utils.ExportScenario
+	for _, s := range scenarios {
+		complete := len(s.Ports) > 0
+		for _, p := range s.Ports {
+			if _, ok := nodes[portNodeID(p.Port)]; !ok {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			result = append(result, s)
+		}
+	}
+	return result
+}
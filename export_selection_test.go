@@ -0,0 +1,192 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func exportTestNode(name, flow, sharedWith string, edges ...v1alpha1.TinyNodeEdge) v1alpha1.TinyNode {
+	node := v1alpha1.TinyNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{v1alpha1.FlowNameLabel: flow},
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha1.TinyNodeSpec{Edges: edges},
+	}
+	if sharedWith != "" {
+		node.Annotations[v1alpha1.SharedWithFlowsAnnotation] = sharedWith
+	}
+	return node
+}
+
+func TestSelectExport(t *testing.T) {
+	flows := []v1alpha1.TinyFlow{
+		{ObjectMeta: metav1.ObjectMeta{Name: "flow-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "flow-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "flow-c"}},
+	}
+	nodes := map[string]v1alpha1.TinyNode{
+		"a1": exportTestNode("a1", "flow-a", "", v1alpha1.TinyNodeEdge{ID: "e1", To: "b1:in", FlowID: "flow-a"}),
+		"a2": exportTestNode("a2", "flow-a", ""),
+		"b1": exportTestNode("b1", "flow-b", ""),
+		// edge of flow-a drawn from a node of flow-b
+		"b2": exportTestNode("b2", "flow-b", "", v1alpha1.TinyNodeEdge{ID: "e2", To: "a2:in", FlowID: "flow-a"}),
+		// legacy edge without a flow id belongs to flow-b
+		"b3": exportTestNode("b3", "flow-b", "", v1alpha1.TinyNodeEdge{ID: "e3", To: "a2:in"}),
+		"c1": exportTestNode("c1", "flow-c", "flow-a"),
+		"c2": exportTestNode("c2", "flow-c", ""),
+	}
+	pages := []v1alpha1.TinyWidgetPage{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "page-1", Annotations: map[string]string{v1alpha1.PageTitleAnnotation: "Overview"}},
+			Spec:       v1alpha1.TinyWidgetPageSpec{Widgets: []v1alpha1.TinyWidget{{Name: "w", Port: "c2:out"}}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		sel       ExportSelection
+		wantErr   bool
+		wantFlows []string
+		wantNodes []string
+		wantPages []string
+	}{
+		{
+			name:    "nothing selected",
+			sel:     ExportSelection{},
+			wantErr: true,
+		},
+		{
+			name:    "unknown flow",
+			sel:     ExportSelection{Flows: []string{"missing"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown page",
+			sel:     ExportSelection{Pages: []string{"missing"}},
+			wantErr: true,
+		},
+		{
+			name:      "flow with shared nodes and edges into other flows",
+			sel:       ExportSelection{Flows: []string{"flow-a"}},
+			wantFlows: []string{"flow-a", "flow-b", "flow-c"},
+			wantNodes: []string{"a1", "a2", "b1", "b2", "c1"},
+		},
+		{
+			name:      "page by title pulls in widget nodes",
+			sel:       ExportSelection{Pages: []string{"Overview"}},
+			wantFlows: []string{"flow-c"},
+			wantNodes: []string{"c2"},
+			wantPages: []string{"page-1"},
+		},
+		{
+			name:      "flow without outside references",
+			sel:       ExportSelection{Flows: []string{"flow-c"}},
+			wantFlows: []string{"flow-c"},
+			wantNodes: []string{"c1", "c2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFlows, gotNodes, gotPages, err := selectExport(tt.sel, flows, nodes, pages)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var flowNames, nodeNames, pageNames []string
+			for _, f := range gotFlows {
+				flowNames = append(flowNames, f.Name)
+			}
+			for name := range gotNodes {
+				nodeNames = append(nodeNames, name)
+			}
+			for _, p := range gotPages {
+				pageNames = append(pageNames, p.Name)
+			}
+			sort.Strings(flowNames)
+			sort.Strings(nodeNames)
+
+			if !reflect.DeepEqual(flowNames, tt.wantFlows) {
+				t.Errorf("flows = %v, want %v", flowNames, tt.wantFlows)
+			}
+			if !reflect.DeepEqual(nodeNames, tt.wantNodes) {
+				t.Errorf("nodes = %v, want %v", nodeNames, tt.wantNodes)
+			}
+			if !reflect.DeepEqual(pageNames, tt.wantPages) {
+				t.Errorf("pages = %v, want %v", pageNames, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestWithSelectedFlowEdges(t *testing.T) {
+	node := exportTestNode("b1", "flow-b", "flow-a",
+		v1alpha1.TinyNodeEdge{ID: "own", To: "b2:in"},
+		v1alpha1.TinyNodeEdge{ID: "other", To: "b2:in", FlowID: "flow-b"},
+		v1alpha1.TinyNodeEdge{ID: "selected", To: "a1:in", FlowID: "flow-a"},
+	)
+	node.Spec.Ports = []v1alpha1.TinyNodePortConfig{
+		{Port: "settings"},
+		{Port: "in", From: "b0:out"},
+		{Port: "in", From: "a1:out", FlowID: "flow-a"},
+	}
+
+	tests := []struct {
+		name      string
+		selected  map[string]bool
+		wantEdges []string
+		wantPorts []string
+	}{
+		{
+			name:      "edges of the selected flow only",
+			selected:  map[string]bool{"flow-a": true},
+			wantEdges: []string{"selected"},
+			wantPorts: []string{"settings", "a1:out"},
+		},
+		{
+			name:      "legacy edges belong to the node's flow",
+			selected:  map[string]bool{"flow-b": true},
+			wantEdges: []string{"own", "other"},
+			wantPorts: []string{"settings", "b0:out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withSelectedFlowEdges(node, tt.selected)
+
+			var edges, ports []string
+			for _, e := range got.Spec.Edges {
+				edges = append(edges, e.ID)
+			}
+			for _, p := range got.Spec.Ports {
+				if p.From == "" {
+					ports = append(ports, p.Port)
+				} else {
+					ports = append(ports, p.From)
+				}
+			}
+			if !reflect.DeepEqual(edges, tt.wantEdges) {
+				t.Errorf("edges = %v, want %v", edges, tt.wantEdges)
+			}
+			if !reflect.DeepEqual(ports, tt.wantPorts) {
+				t.Errorf("ports = %v, want %v", ports, tt.wantPorts)
+			}
+			if len(node.Spec.Edges) != 3 || len(node.Spec.Ports) != 3 {
+				t.Error("input node was modified")
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// DuplicateFlow copies a flow with all nodes it owns into a new flow of the same project.
+// Edges and edge configurations within the flow are rewired to the copies; shared nodes of
+// other flows are not copied but shared with the new flow and connected to it the same way.
+// newName is the display name of the copy, "<name> (copy)" if empty.
+func (a *App) DuplicateFlow(contextName, namespace, projectName, flowResourceName, newName string) (*Flow, error) {
+	if err := a.requireWritable(contextName, namespace, createFlows, createNodes, updateNodes); err != nil {
+		return nil, err
+	}
+
+	if flowResourceName == "" {
+		return nil, fmt.Errorf("flow resource name is required")
+	}
+
+	mgr, err := a.getManager(contextName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	flows, err := mgr.GetFlowList(a.ctx, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get flows: %w", err)
+	}
+	var source *v1alpha1.TinyFlow
+	for i := range flows {
+		if flows[i].Name == flowResourceName {
+			source = &flows[i]
+			break
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("flow %s not found", flowResourceName)
+	}
+	if newName == "" {
+		name := source.Annotations[v1alpha1.FlowDescriptionAnnotation]
+		if name == "" {
+			name = source.Name
+		}
+		newName = name + " (copy)"
+	}
+
+	allNodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project nodes: %w", err)
+	}
+
+	newFlowName, err := mgr.CreateFlow(a.ctx, namespace, projectName, newName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create flow: %w", err)
+	}
+	newFlow := *newFlowName
+
+	// Everything created or changed is journaled, so a failure part way leaves nothing behind
+	journal := &importJournal{}
+	journal.record(ImportChange{Kind: "flow", Name: newFlow, Title: newName, Action: importActionCreated}, func(ctx context.Context) error {
+		// Deleting the flow doesn't remove its nodes, catch copies whose own undo failed
+		if err := mgr.DeleteFlowNodes(ctx, projectName, newFlow); err != nil {
+			return err
+		}
+		return mgr.DeleteFlow(ctx, newFlow)
+	})
+
+	// Name the copies up front so edges between them can be rewired before they are created
+	nameMapping := make(map[string]string)
+	for _, node := range allNodes {
+		if node.Labels[v1alpha1.FlowNameLabel] != flowResourceName {
+			continue
+		}
+		suffix := strconv.FormatInt(time.Now().UnixNano(), 36)[:5]
+		if idx := strings.LastIndex(node.Name, "-"); idx >= 0 && idx < len(node.Name)-1 {
+			suffix = node.Name[idx+1:]
+		}
+		nameMapping[node.Name] = utils.GetNodeGenerateName(projectName, newFlow, node.Spec.Module, node.Spec.Component) + suffix
+	}
+
+	fail := func(err error) (*Flow, error) {
+		rollbackCtx, rollbackCancel := context.WithTimeout(context.Background(), importRollbackTimeout)
+		defer rollbackCancel()
+		_, rollbackErrors := journal.rollback(rollbackCtx, func(string) {})
+		for _, e := range rollbackErrors {
+			a.logger.Info("duplicate rollback error: " + e)
+		}
+		return nil, err
+	}
+
+	for _, node := range allNodes {
+		newNodeName, ok := nameMapping[node.Name]
+		if !ok {
+			continue
+		}
+
+		labels := make(map[string]string, len(node.Labels))
+		for k, v := range node.Labels {
+			labels[k] = v
+		}
+		labels[v1alpha1.FlowNameLabel] = newFlow
+
+		annotations := make(map[string]string, len(node.Annotations))
+		for k, v := range node.Annotations {
+			annotations[k] = v
+		}
+		// The copy isn't shared with the flows the original is shared with
+		delete(annotations, v1alpha1.SharedWithFlowsAnnotation)
+
+		copied := &v1alpha1.TinyNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        newNodeName,
+				Namespace:   namespace,
+				Labels:      labels,
+				Annotations: annotations,
+			},
+			Spec: v1alpha1.TinyNodeSpec{
+				Module:    node.Spec.Module,
+				Component: node.Spec.Component,
+			},
+		}
+		for _, p := range node.Spec.Ports {
+			if p.From == "" {
+				// _control port configuration is runtime state of the original
+				if p.Port != v1alpha1.ControlPort {
+					copied.Spec.Ports = append(copied.Spec.Ports, p)
+				}
+				continue
+			}
+			if edgeFlow(p.FlowID, node) == flowResourceName {
+				copied.Spec.Ports = append(copied.Spec.Ports, duplicatePortConfig(p, nameMapping, newFlow))
+			}
+		}
+		for _, e := range node.Spec.Edges {
+			// Edges drawn in other flows the node is shared with stay with the original
+			if edgeFlow(e.FlowID, node) == flowResourceName {
+				copied.Spec.Edges = append(copied.Spec.Edges, duplicateEdge(e, nameMapping, newFlow))
+			}
+		}
+
+		if err := mgr.CreateNode(a.ctx, copied); err != nil {
+			return fail(fmt.Errorf("failed to create node %s: %w", newNodeName, err))
+		}
+		journal.record(ImportChange{Kind: "node", Name: newNodeName, Title: annotations[v1alpha1.NodeLabelAnnotation], Action: importActionCreated}, func(ctx context.Context) error {
+			return mgr.DeleteNode(ctx, copied)
+		})
+	}
+
+	// Shared nodes of other flows join the copy, with their edges and edge configurations in the flow
+	for _, node := range allNodes {
+		if _, owned := nameMapping[node.Name]; owned || !containsFlow(node.Annotations[v1alpha1.SharedWithFlowsAnnotation], flowResourceName) {
+			continue
+		}
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			fresh, err := mgr.GetNode(a.ctx, node.Name, namespace)
+			if err != nil {
+				return err
+			}
+			if fresh.Annotations == nil {
+				fresh.Annotations = make(map[string]string)
+			}
+			shared := fresh.Annotations[v1alpha1.SharedWithFlowsAnnotation]
+			if !containsFlow(shared, newFlow) {
+				if shared != "" {
+					shared += ","
+				}
+				fresh.Annotations[v1alpha1.SharedWithFlowsAnnotation] = shared + newFlow
+			}
+			for _, e := range fresh.Spec.Edges {
+				if edgeFlow(e.FlowID, *fresh) == flowResourceName {
+					fresh.Spec.Edges = append(fresh.Spec.Edges, duplicateEdge(e, nameMapping, newFlow))
+				}
+			}
+			for _, p := range fresh.Spec.Ports {
+				if p.From != "" && edgeFlow(p.FlowID, *fresh) == flowResourceName {
+					fresh.Spec.Ports = append(fresh.Spec.Ports, duplicatePortConfig(p, nameMapping, newFlow))
+				}
+			}
+			return mgr.UpdateNode(a.ctx, fresh)
+		})
+		if err != nil {
+			return fail(fmt.Errorf("failed to share node %s with the new flow: %w", node.Name, err))
+		}
+		sharedName := node.Name
+		journal.record(ImportChange{Kind: "node", Name: sharedName, Title: node.Annotations[v1alpha1.NodeLabelAnnotation], Action: importActionUpdated}, func(ctx context.Context) error {
+			return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				fresh, err := mgr.GetNode(ctx, sharedName, namespace)
+				if err != nil {
+					return err
+				}
+				unshareFlow(fresh, newFlow)
+				return mgr.UpdateNode(ctx, fresh)
+			})
+		})
+	}
+
+	a.logger.Info("duplicated flow", "source", flowResourceName, "flow", newFlow, "nodes", len(nameMapping))
+
+	return &Flow{
+		Name:         newName,
+		ResourceName: newFlow,
+		NodeCount:    len(nameMapping),
+	}, nil
+}
+
+// duplicateEdge returns a copy of an edge in flow, pointing at the copy of its target if it has one.
+func duplicateEdge(e v1alpha1.TinyNodeEdge, nameMapping map[string]string, flow string) v1alpha1.TinyNodeEdge {
+	targetNode, targetPort := utils.ParseFullPortName(e.To)
+	if newName, ok := nameMapping[targetNode]; ok {
+		e.To = utils.GetPortFullName(newName, targetPort)
+	}
+	e.ID = uuid.New().String()
+	e.FlowID = flow
+	return e
+}
+
+// duplicatePortConfig returns a copy of an edge configuration in flow, from the copy of its source if it has one.
+func duplicatePortConfig(p v1alpha1.TinyNodePortConfig, nameMapping map[string]string, flow string) v1alpha1.TinyNodePortConfig {
+	sourceNode, sourcePort := utils.ParseFullPortName(p.From)
+	if newName, ok := nameMapping[sourceNode]; ok {
+		p.From = utils.GetPortFullName(newName, sourcePort)
+	}
+	p.FlowID = flow
+	return p
+}
+
+// unshareFlow removes a flow from a node's shared flows, along with the node's edges and
+// edge configurations in that flow.
+func unshareFlow(node *v1alpha1.TinyNode, flow string) {
+	if shared := node.Annotations[v1alpha1.SharedWithFlowsAnnotation]; shared != "" {
+		var kept []string
+		for _, f := range strings.Split(shared, ",") {
+			if f != flow {
+				kept = append(kept, f)
+			}
+		}
+		if len(kept) == 0 {
+			delete(node.Annotations, v1alpha1.SharedWithFlowsAnnotation)
+		} else {
+			node.Annotations[v1alpha1.SharedWithFlowsAnnotation] = strings.Join(kept, ",")
+		}
+	}
+
+	edges := node.Spec.Edges[:0]
+	for _, e := range node.Spec.Edges {
+		if e.FlowID != flow {
+			edges = append(edges, e)
+		}
+	}
+	node.Spec.Edges = edges
+
+	ports := node.Spec.Ports[:0]
+	for _, p := range node.Spec.Ports {
+		if p.From == "" || p.FlowID != flow {
+			ports = append(ports, p)
+		}
+	}
+	node.Spec.Ports = ports
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDuplicateEdge(t *testing.T) {
+	nameMapping := map[string]string{"ticker": "copy-ticker", "debug": "copy-debug"}
+
+	tests := []struct {
+		name   string
+		edge   v1alpha1.TinyNodeEdge
+		wantTo string
+	}{
+		{
+			name:   "target inside the copied flow",
+			edge:   v1alpha1.TinyNodeEdge{ID: "e1", Port: "out", To: "debug:in", FlowID: "flow-a"},
+			wantTo: "copy-debug:in",
+		},
+		{
+			name:   "target of another flow stays",
+			edge:   v1alpha1.TinyNodeEdge{ID: "e2", Port: "out", To: "shared:in", FlowID: "flow-a"},
+			wantTo: "shared:in",
+		},
+		{
+			name:   "legacy edge without a flow id",
+			edge:   v1alpha1.TinyNodeEdge{ID: "e3", Port: "out", To: "ticker:_control"},
+			wantTo: "copy-ticker:_control",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := duplicateEdge(tt.edge, nameMapping, "flow-copy")
+			if got.To != tt.wantTo {
+				t.Errorf("To = %q, want %q", got.To, tt.wantTo)
+			}
+			if got.FlowID != "flow-copy" {
+				t.Errorf("FlowID = %q, want flow-copy", got.FlowID)
+			}
+			if got.Port != tt.edge.Port {
+				t.Errorf("Port = %q, want %q", got.Port, tt.edge.Port)
+			}
+			if got.ID == "" || got.ID == tt.edge.ID {
+				t.Errorf("ID = %q, want a new one", got.ID)
+			}
+		})
+	}
+}
+
+func TestUnshareFlow(t *testing.T) {
+	edges := []v1alpha1.TinyNodeEdge{
+		{ID: "own", To: "a:in", FlowID: "flow-a"},
+		{ID: "copied", To: "b:in", FlowID: "flow-copy"},
+	}
+	ports := []v1alpha1.TinyNodePortConfig{
+		{Port: "settings"},
+		{Port: "in", From: "a:out", FlowID: "flow-a"},
+		{Port: "in", From: "b:out", FlowID: "flow-copy"},
+	}
+
+	tests := []struct {
+		name       string
+		sharedWith string
+		wantShared string
+		wantEdges  []string
+		wantPorts  []string
+	}{
+		{
+			name:       "one of several shared flows",
+			sharedWith: "flow-b,flow-copy,flow-c",
+			wantShared: "flow-b,flow-c",
+			wantEdges:  []string{"own"},
+			wantPorts:  []string{"settings", "a:out"},
+		},
+		{
+			name:       "last shared flow drops the annotation",
+			sharedWith: "flow-copy",
+			wantEdges:  []string{"own"},
+			wantPorts:  []string{"settings", "a:out"},
+		},
+		{
+			name:       "not shared with the flow",
+			sharedWith: "flow-b",
+			wantShared: "flow-b",
+			wantEdges:  []string{"own"},
+			wantPorts:  []string{"settings", "a:out"},
+		},
+		{
+			name:      "not shared at all",
+			wantEdges: []string{"own"},
+			wantPorts: []string{"settings", "a:out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &v1alpha1.TinyNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "shared",
+					Labels:      map[string]string{v1alpha1.FlowNameLabel: "flow-a"},
+					Annotations: map[string]string{},
+				},
+				Spec: v1alpha1.TinyNodeSpec{
+					Edges: append([]v1alpha1.TinyNodeEdge(nil), edges...),
+					Ports: append([]v1alpha1.TinyNodePortConfig(nil), ports...),
+				},
+			}
+			if tt.sharedWith != "" {
+				node.Annotations[v1alpha1.SharedWithFlowsAnnotation] = tt.sharedWith
+			}
+
+			unshareFlow(node, "flow-copy")
+
+			shared, ok := node.Annotations[v1alpha1.SharedWithFlowsAnnotation]
+			if shared != tt.wantShared || ok != (tt.wantShared != "") {
+				t.Errorf("shared with = %q (set %v), want %q", shared, ok, tt.wantShared)
+			}
+			var gotEdges, gotPorts []string
+			for _, e := range node.Spec.Edges {
+				gotEdges = append(gotEdges, e.ID)
+			}
+			for _, p := range node.Spec.Ports {
+				if p.From == "" {
+					gotPorts = append(gotPorts, p.Port)
+				} else {
+					gotPorts = append(gotPorts, p.From)
+				}
+			}
+			if !reflect.DeepEqual(gotEdges, tt.wantEdges) {
+				t.Errorf("edges = %v, want %v", gotEdges, tt.wantEdges)
+			}
+			if !reflect.DeepEqual(gotPorts, tt.wantPorts) {
+				t.Errorf("ports = %v, want %v", gotPorts, tt.wantPorts)
+			}
+		})
+	}
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/utils"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// maxFlowRevisions is how many revisions are kept per flow, older ones are removed
+	maxFlowRevisions = 50
+
+	flowRevisionIDFormat = "20060102T150405.000Z"
+)
+
+// FlowRevision describes a saved snapshot of a flow.
+type FlowRevision struct {
+	ID        string `json:"id"`
+	Flow      string `json:"flow"`
+	Author    string `json:"author"`
+	Message   string `json:"message,omitempty"`
+	CreatedAt string `json:"createdAt"` // RFC 3339
+	Nodes     int    `json:"nodes"`
+	Edges     int    `json:"edges"`
+}
+
+// storedFlowRevision is a revision as written to disk: the flow as a project export of only
+// that flow, like ExportProjectSelection produces.
+type storedFlowRevision struct {
+	FlowRevision
+	Export *utils.ProjectExport `json:"export"`
+}
+
+// RevisionDiffItem is a node or edge that differs between two revisions.
+type RevisionDiffItem struct {
+	Kind    string              `json:"kind"`   // node or edge
+	Action  string              `json:"action"` // added, removed or changed
+	ID      string              `json:"id"`
+	Title   string              `json:"title,omitempty"`
+	Changes []ImportFieldChange `json:"changes,omitempty"`
+}
+
+// SaveFlowRevision snapshots the nodes, edges and port configurations of a flow. Revisions are
+// stored locally per cluster context and namespace, labelled with the OS user and the time.
+func (a *App) SaveFlowRevision(contextName, namespace, projectName, flowResourceName, message string) (*FlowRevision, error) {
+	export, err := a.flowRevisionExport(contextName, namespace, projectName, flowResourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := flowRevisionsDir(contextName, namespace, projectName, flowResourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	author := ""
+	if usr, err := user.Current(); err == nil {
+		author = usr.Username
+	}
+
+	now := time.Now().UTC()
+	rev := storedFlowRevision{
+		FlowRevision: FlowRevision{
+			ID:        now.Format(flowRevisionIDFormat),
+			Flow:      flowResourceName,
+			Author:    author,
+			Message:   message,
+			CreatedAt: now.Format(time.RFC3339),
+		},
+		Export: export,
+	}
+	for _, elem := range export.Elements {
+		if isNodeElement(elem) {
+			rev.Nodes++
+		} else {
+			rev.Edges++
+		}
+	}
+
+	data, err := json.MarshalIndent(rev, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, rev.ID+".json"), data, 0600); err != nil {
+		return nil, fmt.Errorf("unable to save revision: %w", err)
+	}
+	a.logger.Info("saved flow revision", "flow", flowResourceName, "revision", rev.ID, "nodes", rev.Nodes, "edges", rev.Edges)
+
+	pruneFlowRevisions(dir)
+	return &rev.FlowRevision, nil
+}
+
+// ListFlowRevisions returns the saved revisions of a flow, newest first.
+func (a *App) ListFlowRevisions(contextName, namespace, projectName, flowResourceName string) ([]FlowRevision, error) {
+	dir, err := flowRevisionsDir(contextName, namespace, projectName, flowResourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []FlowRevision{}
+	for _, id := range flowRevisionIDs(dir) {
+		rev, err := loadFlowRevision(dir, id)
+		if err != nil {
+			a.logger.Error(err, "skipping unreadable flow revision", "revision", id)
+			continue
+		}
+		revisions = append(revisions, rev.FlowRevision)
+	}
+	return revisions, nil
+}
+
+// DiffFlowRevisions lists the nodes and edges that differ between two revisions of a flow.
+// An empty revision ID stands for the flow as it is in the cluster now.
+func (a *App) DiffFlowRevisions(contextName, namespace, projectName, flowResourceName, fromID, toID string) ([]RevisionDiffItem, error) {
+	load := func(id string) ([]map[string]interface{}, error) {
+		if id == "" {
+			export, err := a.flowRevisionExport(contextName, namespace, projectName, flowResourceName)
+			if err != nil {
+				return nil, err
+			}
+			// Stored revisions hold decoded JSON, round-trip the live flow the same way
+			decoded, err := decodedExport(export)
+			if err != nil {
+				return nil, err
+			}
+			return decoded.Elements, nil
+		}
+		dir, err := flowRevisionsDir(contextName, namespace, projectName, flowResourceName)
+		if err != nil {
+			return nil, err
+		}
+		rev, err := loadFlowRevision(dir, id)
+		if err != nil {
+			return nil, err
+		}
+		return rev.Export.Elements, nil
+	}
+
+	before, err := load(fromID)
+	if err != nil {
+		return nil, err
+	}
+	after, err := load(toID)
+	if err != nil {
+		return nil, err
+	}
+	return diffRevisionElements(before, after), nil
+}
+
+// RestoreFlowRevision puts a flow back to a saved revision: nodes added since are deleted,
+// the revision's nodes are recreated or updated and the flow's edges replaced. The current
+// state is saved as a revision first, so a restore can itself be reverted.
+func (a *App) RestoreFlowRevision(contextName, namespace, projectName, flowResourceName, revisionID string) (*ImportResult, error) {
+	if err := a.requireWritable(contextName, namespace, createNodes, updateNodes, deleteNodes, createFlows, updateFlows); err != nil {
+		return nil, err
+	}
+
+	dir, err := flowRevisionsDir(contextName, namespace, projectName, flowResourceName)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := loadFlowRevision(dir, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), importTimeout)
+	defer timeoutCancel()
+
+	ctx, cancel := context.WithCancelCause(timeoutCtx)
+	defer cancel(nil)
+
+	if err := a.beginImport(cancel); err != nil {
+		return nil, err
+	}
+	defer a.endImport()
+
+	emitProgress := func(msg string) {
+		wailsruntime.EventsEmit(a.ctx, "import:progress", msg)
+	}
+
+	emitProgress("Saving current state...")
+	if _, err := a.SaveFlowRevision(contextName, namespace, projectName, flowResourceName, "Before restoring "+revisionID); err != nil {
+		return nil, fmt.Errorf("unable to save current state: %w", err)
+	}
+
+	mgr, err := a.getManager(contextName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool)
+	for _, elem := range rev.Export.Elements {
+		if isNodeElement(elem) {
+			id, _ := elem["id"].(string)
+			keep[id] = true
+		}
+	}
+
+	nodes, err := mgr.GetProjectNodes(ctx, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get project nodes: %w", err)
+	}
+	var added []*v1alpha1.TinyNode
+	for i := range nodes {
+		if nodes[i].Labels[v1alpha1.FlowNameLabel] == flowResourceName && !keep[nodes[i].Name] {
+			added = append(added, &nodes[i])
+		}
+	}
+
+	_, result, err := a.applyImport(ctx, contextName, namespace, projectName, rev.Export, ImportOptions{
+		Selection:      &ImportSelection{Flows: []string{flowResourceName}},
+		pruneFlowEdges: true,
+	}, emitProgress)
+	if err != nil {
+		return result, err
+	}
+
+	// Nodes added after the revision are only deleted once the import went through, a failed
+	// import is rolled back and leaves them in place
+	for _, node := range added {
+		emitProgress(fmt.Sprintf("Deleting %s, added after the revision...", nodeTitle(node)))
+		if err := mgr.DeleteNode(ctx, node); err != nil && !apierrors.IsNotFound(err) {
+			return result, fmt.Errorf("revision restored, but unable to delete node %s added after it: %w", node.Name, err)
+		}
+		if err := mgr.CleanupNodeReferences(ctx, projectName, node.Name); err != nil {
+			a.logger.Error(err, "failed to clean up edges after node deletion")
+		}
+		result.Applied = append(result.Applied, ImportChange{Kind: "node", Name: node.Name, Title: nodeTitle(node), Action: importActionDeleted})
+	}
+	a.logger.Info("restored flow revision", "flow", flowResourceName, "revision", revisionID)
+	return result, nil
+}
+
+// flowRevisionExport exports a flow with the nodes of other flows it references. Project
+// description, pages and scenarios aren't part of a flow revision.
+func (a *App) flowRevisionExport(contextName, namespace, projectName, flowResourceName string) (*utils.ProjectExport, error) {
+	export, err := a.buildProjectExport(contextName, namespace, projectName, &ExportSelection{Flows: []string{flowResourceName}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to export flow: %w", err)
+	}
+	export.Description = ""
+	export.Pages = nil
+	export.Scenarios = nil
+	return export, nil
+}
+
+// flowRevisionsDir returns the directory revisions of a flow are stored in, creating it if needed.
+func flowRevisionsDir(contextName, namespace, projectName, flowResourceName string) (string, error) {
+	usr, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	// Context names can hold characters that aren't valid in file names, e.g. EKS ARNs
+	dir := filepath.Join(usr.HomeDir, ".config", "tinysystems", "revisions",
+		url.QueryEscape(contextName), url.QueryEscape(namespace), url.QueryEscape(projectName), url.QueryEscape(flowResourceName))
+	// Revisions hold port configurations as they are, secrets included
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// flowRevisionIDs returns the IDs of the revisions in dir, newest first.
+func flowRevisionIDs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, e := range entries {
+		if id, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	// IDs are timestamps, so they sort by time
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids
+}
+
+// loadFlowRevision reads a stored revision.
+func loadFlowRevision(dir, id string) (*storedFlowRevision, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid revision %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("revision %s not found", id)
+		}
+		return nil, err
+	}
+	var rev storedFlowRevision
+	if err := json.Unmarshal(data, &rev); err != nil {
+		return nil, fmt.Errorf("invalid revision %s: %w", id, err)
+	}
+	if rev.Export == nil {
+		return nil, fmt.Errorf("revision %s has no flow data", id)
+	}
+	return &rev, nil
+}
+
+// pruneFlowRevisions removes all but the newest maxFlowRevisions revisions in dir.
+func pruneFlowRevisions(dir string) {
+	ids := flowRevisionIDs(dir)
+	for i := maxFlowRevisions; i < len(ids); i++ {
+		_ = os.Remove(filepath.Join(dir, ids[i]+".json"))
+	}
+}
+
+// diffRevisionElements compares export elements of two revisions. Nodes are matched by ID,
+// edges by the ports they connect, as edge IDs change when a flow is restored.
+func diffRevisionElements(before, after []map[string]interface{}) []RevisionDiffItem {
+	beforeNodes, beforeEdges := indexRevisionElements(before)
+	afterNodes, afterEdges := indexRevisionElements(after)
+
+	var items []RevisionDiffItem
+	for _, id := range unionKeys(beforeNodes, afterNodes) {
+		b, inBefore := beforeNodes[id]
+		c, inAfter := afterNodes[id]
+		switch {
+		case !inBefore:
+			items = append(items, RevisionDiffItem{Kind: "node", Action: diffActionAdded, ID: id, Title: elementLabel(c)})
+		case !inAfter:
+			items = append(items, RevisionDiffItem{Kind: "node", Action: diffActionRemoved, ID: id, Title: elementLabel(b)})
+		default:
+			if changes := diffNodeElements(b, c); len(changes) > 0 {
+				items = append(items, RevisionDiffItem{Kind: "node", Action: diffActionChanged, ID: id, Title: elementLabel(c), Changes: changes})
+			}
+		}
+	}
+	for _, key := range unionKeys(beforeEdges, afterEdges) {
+		b, inBefore := beforeEdges[key]
+		c, inAfter := afterEdges[key]
+		switch {
+		case !inBefore:
+			items = append(items, RevisionDiffItem{Kind: "edge", Action: diffActionAdded, ID: key})
+		case !inAfter:
+			items = append(items, RevisionDiffItem{Kind: "edge", Action: diffActionRemoved, ID: key})
+		default:
+			bData, _ := b["data"].(map[string]interface{})
+			cData, _ := c["data"].(map[string]interface{})
+			if change, ok := diffJSONField("configuration", bData["configuration"], cData["configuration"]); ok {
+				items = append(items, RevisionDiffItem{Kind: "edge", Action: diffActionChanged, ID: key, Changes: []ImportFieldChange{change}})
+			}
+		}
+	}
+	return items
+}
+
+// indexRevisionElements maps node elements by ID and edge elements by "source:port->target:port".
+func indexRevisionElements(elements []map[string]interface{}) (nodes, edges map[string]map[string]interface{}) {
+	nodes = make(map[string]map[string]interface{})
+	edges = make(map[string]map[string]interface{})
+	for _, elem := range elements {
+		if isNodeElement(elem) {
+			id, _ := elem["id"].(string)
+			nodes[id] = elem
+			continue
+		}
+		source, _ := elem["source"].(string)
+		sourceHandle, _ := elem["sourceHandle"].(string)
+		target, _ := elem["target"].(string)
+		targetHandle, _ := elem["targetHandle"].(string)
+		edges[source+":"+sourceHandle+"->"+target+":"+targetHandle] = elem
+	}
+	return nodes, edges
+}
+
+// diffNodeElements lists position, display and handle configuration changes of a node element.
+func diffNodeElements(before, after map[string]interface{}) []ImportFieldChange {
+	var changes []ImportFieldChange
+	if change, ok := diffJSONField("position", before["position"], after["position"]); ok {
+		changes = append(changes, change)
+	}
+
+	beforeData, _ := before["data"].(map[string]interface{})
+	afterData, _ := after["data"].(map[string]interface{})
+	for _, field := range []string{"module", "component", "label", "spin", "dashboard", "shared_with_flows"} {
+		if change, ok := diffJSONField(field, beforeData[field], afterData[field]); ok {
+			changes = append(changes, change)
+		}
+	}
+
+	handleConfigs := func(data map[string]interface{}) map[string]interface{} {
+		configs := make(map[string]interface{})
+		handles, _ := data["handles"].([]interface{})
+		for _, h := range handles {
+			handle, _ := h.(map[string]interface{})
+			id, _ := handle["id"].(string)
+			if config, ok := handle["configuration"]; ok && id != "" {
+				configs[id] = config
+			}
+		}
+		return configs
+	}
+	beforeConfigs, afterConfigs := handleConfigs(beforeData), handleConfigs(afterData)
+	for _, port := range unionKeys(beforeConfigs, afterConfigs) {
+		if change, ok := diffJSONField("ports["+port+"].configuration", beforeConfigs[port], afterConfigs[port]); ok {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// diffJSONField compares two decoded JSON values, returning their encodings if they differ.
+func diffJSONField(field string, before, after interface{}) (ImportFieldChange, bool) {
+	b := compactJSON(before)
+	c := compactJSON(after)
+	if b == c {
+		return ImportFieldChange{}, false
+	}
+	return ImportFieldChange{Field: field, Before: b, After: c}, true
+}
+
+// compactJSON encodes a decoded JSON value with sorted keys, "" for nil. Configurations
+// stored as JSON strings are decoded first so formatting differences don't count.
+func compactJSON(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(s), &decoded); err == nil {
+			v = decoded
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// elementLabel returns the display label of a node element.
+func elementLabel(elem map[string]interface{}) string {
+	data, _ := elem["data"].(map[string]interface{})
+	label, _ := data["label"].(string)
+	return label
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRevisionElements(t *testing.T) {
+	elements := func(t *testing.T, s string) []map[string]interface{} {
+		t.Helper()
+		var result []map[string]interface{}
+		for _, v := range parseTestJSON(t, s).([]interface{}) {
+			result = append(result, v.(map[string]interface{}))
+		}
+		return result
+	}
+
+	const base = `[
+		{"id":"ticker","type":"tinyNode","position":{"x":10,"y":20},"data":{"label":"Ticker","component":"ticker","handles":[{"id":"settings","configuration":{"delay":1000}}]}},
+		{"id":"debug","type":"tinyNode","position":{"x":200,"y":20},"data":{"label":"Debug","component":"debug"}},
+		{"id":"e1","type":"tinyEdge","source":"ticker","sourceHandle":"out","target":"debug","targetHandle":"in","data":{"configuration":{"context":"{{$}}"}}}
+	]`
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []RevisionDiffItem
+	}{
+		{
+			name:   "identical",
+			before: base,
+			after:  base,
+			want:   nil,
+		},
+		{
+			name:   "restored edge with a new id is unchanged",
+			before: base,
+			after: `[
+				{"id":"ticker","type":"tinyNode","position":{"x":10,"y":20},"data":{"label":"Ticker","component":"ticker","handles":[{"id":"settings","configuration":"{\"delay\": 1000}"}]}},
+				{"id":"debug","type":"tinyNode","position":{"x":200,"y":20},"data":{"label":"Debug","component":"debug"}},
+				{"id":"e2","type":"tinyEdge","source":"ticker","sourceHandle":"out","target":"debug","targetHandle":"in","data":{"configuration":{"context":"{{$}}"}}}
+			]`,
+			want: nil,
+		},
+		{
+			name:   "added and removed nodes and edges",
+			before: base,
+			after: `[
+				{"id":"ticker","type":"tinyNode","position":{"x":10,"y":20},"data":{"label":"Ticker","component":"ticker","handles":[{"id":"settings","configuration":{"delay":1000}}]}},
+				{"id":"log","type":"tinyNode","position":{"x":200,"y":20},"data":{"label":"Log"}},
+				{"id":"e1","type":"tinyEdge","source":"ticker","sourceHandle":"out","target":"log","targetHandle":"in","data":{}}
+			]`,
+			want: []RevisionDiffItem{
+				{Kind: "node", Action: diffActionRemoved, ID: "debug", Title: "Debug"},
+				{Kind: "node", Action: diffActionAdded, ID: "log", Title: "Log"},
+				{Kind: "edge", Action: diffActionRemoved, ID: "ticker:out->debug:in"},
+				{Kind: "edge", Action: diffActionAdded, ID: "ticker:out->log:in"},
+			},
+		},
+		{
+			name:   "changed node fields, port and edge configurations",
+			before: base,
+			after: `[
+				{"id":"ticker","type":"tinyNode","position":{"x":15,"y":20},"data":{"label":"Clock","component":"ticker","handles":[{"id":"settings","configuration":{"delay":500}}]}},
+				{"id":"debug","type":"tinyNode","position":{"x":200,"y":20},"data":{"label":"Debug","component":"debug"}},
+				{"id":"e1","type":"tinyEdge","source":"ticker","sourceHandle":"out","target":"debug","targetHandle":"in","data":{"configuration":{"context":"{{$.tick}}"}}}
+			]`,
+			want: []RevisionDiffItem{
+				{Kind: "node", Action: diffActionChanged, ID: "ticker", Title: "Clock", Changes: []ImportFieldChange{
+					{Field: "position", Before: `{"x":10,"y":20}`, After: `{"x":15,"y":20}`},
+					{Field: "label", Before: `"Ticker"`, After: `"Clock"`},
+					{Field: "ports[settings].configuration", Before: `{"delay":1000}`, After: `{"delay":500}`},
+				}},
+				{Kind: "edge", Action: diffActionChanged, ID: "ticker:out->debug:in", Changes: []ImportFieldChange{
+					{Field: "configuration", Before: `{"context":"{{$}}"}`, After: `{"context":"{{$.tick}}"}`},
+				}},
+			},
+		},
+		{
+			name:   "from an empty revision",
+			before: `[]`,
+			after:  `[{"id":"debug","type":"tinyNode","data":{"label":"Debug"}}]`,
+			want: []RevisionDiffItem{
+				{Kind: "node", Action: diffActionAdded, ID: "debug", Title: "Debug"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffRevisionElements(elements(t, tt.before), elements(t, tt.after))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffRevisionElements() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
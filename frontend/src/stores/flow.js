@@ -4,6 +4,12 @@ import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime'
 
 const GoApp = window.go?.main?.App
 
+// Unsubscribes the watch:status listener of the current flow
+let offWatchStatus = null
+
+// Unsubscribes the edit:history listener of the current flow
+let offEditHistory = null
+
 function clone(obj) {
   try {
     return JSON.parse(JSON.stringify(obj))
@@ -44,8 +50,12 @@ export const useFlowStore = defineStore('flowStore', {
       lastUpdate: null,
       animationCheckInterval: null,
       watching: false,
+      watchSubscription: null, // { id, event } returned by WatchFlowNodes
+      watchStatus: null, // last watch:status of this flow (connected/reconnecting/failed)
       trace: null, // Selected trace ID for using real runtime data
-      readOnly: true
+      history: { canUndo: false, canRedo: false }, // undo/redo state of the edit session
+      readOnly: true,
+      forbidden: false // the user's role doesn't allow editing in this namespace
     }
   },
   getters: {
@@ -349,6 +359,25 @@ export const useFlowStore = defineStore('flowStore', {
           })
         }
 
+        // Undo history starts fresh with every flow opened
+        await GoApp.BeginEditSession(contextName, namespace, this.projectResourceName, this.flowResourceName)
+        this.history = { canUndo: false, canRedo: false }
+        if (offEditHistory) offEditHistory()
+        offEditHistory = EventsOn('edit:history', (state) => {
+          this.history = state
+        })
+
+        // Viewers can't leave read-only mode
+        try {
+          const capabilities = await GoApp.GetCapabilities(contextName, namespace)
+          this.forbidden = !!capabilities?.readOnly
+        } catch (e) {
+          this.forbidden = false
+        }
+        if (this.forbidden) {
+          this.readOnly = true
+        }
+
         this.ready = true
         return data
       } finally {
@@ -360,14 +389,21 @@ export const useFlowStore = defineStore('flowStore', {
 
       this.watching = true
       try {
-        await GoApp.WatchFlowNodes(
+        const subscription = await GoApp.WatchFlowNodes(
           this.contextName,
           this.namespace,
           this.projectResourceName,
           this.flowResourceName
         )
+        this.watchSubscription = subscription
 
-        EventsOn('flowNodeUpdate', (event) => {
+        offWatchStatus = EventsOn('watch:status', (status) => {
+          if (status.context !== this.contextName || status.namespace !== this.namespace || status.project !== this.projectResourceName) return
+          if (status.flow && status.flow !== this.flowResourceName) return
+          this.watchStatus = status
+        })
+
+        EventsOn(subscription.event, (event) => {
           this.processNodeEvent(event)
         })
 
@@ -381,8 +417,17 @@ export const useFlowStore = defineStore('flowStore', {
       if (!GoApp || !this.watching) return
 
       try {
-        EventsOff('flowNodeUpdate')
-        await GoApp.StopWatchFlowNodes()
+        const subscription = this.watchSubscription
+        this.watchSubscription = null
+        this.watchStatus = null
+        if (offWatchStatus) {
+          offWatchStatus()
+          offWatchStatus = null
+        }
+        if (subscription) {
+          EventsOff(subscription.event)
+          await GoApp.StopWatchFlowNodes(subscription.id)
+        }
         this.watching = false
         this.stopAnimationCheck()
       } catch (err) {
@@ -392,10 +437,17 @@ export const useFlowStore = defineStore('flowStore', {
     clean() {
       this.elements = []
       this.trace = null
+      this.history = { canUndo: false, canRedo: false }
+      if (offEditHistory) {
+        offEditHistory()
+        offEditHistory = null
+      }
+      GoApp?.EndEditSession()
       this.stopAnimationCheck()
       this.stopWatching()
       this.ready = false
       this.readOnly = true
+      this.forbidden = false
       localStorage.removeItem('flowEditorReadOnly')
     },
     setMeta(meta) {
@@ -662,6 +714,72 @@ export const useFlowStore = defineStore('flowStore', {
         this.loading = false
       }
     },
+    async copyNodes() {
+      if (!GoApp) throw new Error('Wails runtime not available')
+
+      const nodeIds = this.selectedNodes.map((n) => n.id)
+      if (nodeIds.length === 0) return 0
+
+      const payload = await GoApp.CopyNodes(this.contextName, this.namespace, this.projectResourceName, nodeIds)
+      await navigator.clipboard.writeText(payload)
+      return nodeIds.length
+    },
+    async pasteNodes() {
+      if (!GoApp) throw new Error('Wails runtime not available')
+
+      const payload = await navigator.clipboard.readText()
+      if (!payload) return []
+
+      this.loadingAlt = true
+      try {
+        // New nodes appear through the flow watch
+        return await GoApp.PasteNodes(
+          this.contextName,
+          this.namespace,
+          this.projectResourceName,
+          this.flowResourceName,
+          payload,
+          40,
+          40
+        )
+      } finally {
+        this.loadingAlt = false
+      }
+    },
+    async undo() {
+      if (!GoApp) throw new Error('Wails runtime not available')
+      if (!this.history.canUndo) return
+
+      this.loadingAlt = true
+      try {
+        await GoApp.Undo()
+        await this.reloadElements()
+      } finally {
+        this.loadingAlt = false
+      }
+    },
+    async redo() {
+      if (!GoApp) throw new Error('Wails runtime not available')
+      if (!this.history.canRedo) return
+
+      this.loadingAlt = true
+      try {
+        await GoApp.Redo()
+        await this.reloadElements()
+      } finally {
+        this.loadingAlt = false
+      }
+    },
+    // Replaces the elements with the flow as stored, e.g. after undo put back edges
+    async reloadElements() {
+      const data = await GoApp.GetFlowForEditor(this.contextName, this.namespace, this.projectResourceName, this.flowResourceName)
+      this.elements = []
+      if (data.elements) {
+        data.elements.forEach((el) => {
+          this.addElement({ id: el.id, graph: el })
+        })
+      }
+    },
     export() {
       let copy = clone(this.elements)
       Object.keys(copy).forEach((key) => {
@@ -793,12 +911,13 @@ export const useFlowStore = defineStore('flowStore', {
       this.highlightTrace(traceId)
     },
     toggleReadOnly() {
+      if (this.forbidden) return
       this.readOnly = !this.readOnly
       localStorage.setItem('flowEditorReadOnly', JSON.stringify(this.readOnly))
     },
     initReadOnly() {
       const stored = localStorage.getItem('flowEditorReadOnly')
-      if (stored !== null) {
+      if (stored !== null && !this.forbidden) {
         this.readOnly = JSON.parse(stored)
       }
     },
@@ -3,20 +3,36 @@
 import {main} from '../models';
 import {kubernetes} from '../models';
 
+export function AddKubeconfigDirectory():Promise<string>;
+
+export function AddKubeconfigFile():Promise<string>;
+
 export function AddNode(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string,arg8:string,arg9:number,arg10:number):Promise<Record<string, any>>;
 
 export function ApplyTraceToFlow(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.ApplyTraceToFlowResponse>;
 
 export function BatchUpdateNodePositions(arg1:string,arg2:string,arg3:Record<string, main.NodePosition>):Promise<void>;
 
+export function BeginEditSession(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function CancelImport():Promise<void>;
+
 export function CheckAuthorization(arg1:string):Promise<void>;
 
 export function CheckOtelCollector(arg1:string,arg2:string):Promise<main.OtelCollectorStatus>;
 
+export function CloneProject(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string):Promise<main.CloneResult>;
+
 export function ConnectNodes(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string,arg8:string):Promise<void>;
 
 export function ConnectToCluster(arg1:string):Promise<kubernetes.Clientset>;
 
+export function ConvertExportToYAML(arg1:string):Promise<string>;
+
+export function ConvertImportToJSON(arg1:string):Promise<string>;
+
+export function CopyNodes(arg1:string,arg2:string,arg3:string,arg4:Array<string>):Promise<string>;
+
 export function CreateDashboardPage(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.WidgetPage>;
 
 export function CreateFlow(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.Flow>;
@@ -31,10 +47,30 @@ export function DeleteNode(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function DeleteProject(arg1:string,arg2:string,arg3:string):Promise<void>;
 
+export function DiffFlowRevisions(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string):Promise<Array<main.RevisionDiffItem>>;
+
+export function DiffFlows(arg1:main.DiffSource,arg2:main.DiffSource):Promise<main.ProjectDiff>;
+
+export function DiffProjects(arg1:main.DiffSource,arg2:main.DiffSource):Promise<main.ProjectDiff>;
+
 export function DisconnectNodes(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
 
+export function DuplicateFlow(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.Flow>;
+
+export function EndEditSession():Promise<void>;
+
 export function ExportProject(arg1:string,arg2:string,arg3:string):Promise<string>;
 
+export function ExportProjectManifests(arg1:string,arg2:string,arg3:string,arg4:main.ExportSelection):Promise<string>;
+
+export function ExportProjectManifestsRedacted(arg1:string,arg2:string,arg3:string,arg4:main.ExportSelection,arg5:main.RedactionOptions):Promise<main.RedactedExport>;
+
+export function ExportProjectManifestsToDirectory(arg1:string,arg2:string,arg3:string,arg4:main.RedactionOptions):Promise<string>;
+
+export function ExportProjectRedacted(arg1:string,arg2:string,arg3:string,arg4:main.ExportSelection,arg5:main.RedactionOptions):Promise<main.RedactedExport>;
+
+export function ExportProjectSelection(arg1:string,arg2:string,arg3:string,arg4:main.ExportSelection):Promise<string>;
+
 export function FetchSolutionExport(arg1:string,arg2:string):Promise<string>;
 
 export function FetchSolutionJSON(arg1:string):Promise<string>;
@@ -43,6 +79,12 @@ export function GetAvailableComponents(arg1:string,arg2:string):Promise<Array<ma
 
 export function GetBuildInfo():Promise<main.BuildInfo>;
 
+export function GetCapabilities(arg1:string,arg2:string):Promise<main.Capabilities>;
+
+export function GetClusterHealth():Promise<main.ClusterHealth>;
+
+export function GetEditHistory():Promise<main.EditHistoryState>;
+
 export function GetFlowForEditor(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.FlowEditorData>;
 
 export function GetFlowGraph(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Record<string, any>>;
@@ -53,6 +95,8 @@ export function GetFlowsWithGraphs(arg1:string,arg2:string,arg3:string):Promise<
 
 export function GetKubeContexts():Promise<Array<main.KubeContext>>;
 
+export function GetKubeconfigSources():Promise<Array<main.KubeconfigSource>>;
+
 export function GetModules(arg1:string,arg2:string):Promise<Array<main.Module>>;
 
 export function GetNamespaces(arg1:string):Promise<Array<string>>;
@@ -75,24 +119,54 @@ export function GetTraceByID(arg1:string,arg2:string,arg3:string,arg4:string):Pr
 
 export function GetTraces(arg1:string,arg2:string,arg3:string,arg4:string,arg5:number,arg6:number,arg7:number):Promise<main.TracesResponse>;
 
+export function GetWatchSubscriptions():Promise<Array<main.WatchSubscriptionStatus>>;
+
 export function GetWidgetPages(arg1:string,arg2:string,arg3:string):Promise<Array<main.WidgetPage>>;
 
 export function GetWidgets(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Array<main.Widget>>;
 
-export function ImportProject(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+export function ImportKubeconfig(arg1:string,arg2:string):Promise<string>;
+
+export function ImportProject(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.ImportResult>;
+
+export function ImportProjectWithOptions(arg1:string,arg2:string,arg3:string,arg4:string,arg5:main.ImportOptions):Promise<main.ImportResult>;
 
 export function InspectNodePort(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string):Promise<Record<string, any>>;
 
+export function ListFlowRevisions(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Array<main.FlowRevision>>;
+
+export function ListImportVariables(arg1:string,arg2:main.ImportOptions):Promise<Array<main.ImportVariable>>;
+
+export function LoadImportVariablesFile():Promise<Record<string, string>>;
+
+export function LoadImportVariablesFromSecret(arg1:string,arg2:string,arg3:string):Promise<Record<string, string>>;
+
 export function OpenFile():Promise<string>;
 
+export function PasteNodes(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:number,arg7:number):Promise<Array<main.CloneMapping>>;
+
+export function PlanImport(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.ImportPlan>;
+
+export function PlanImportWithOptions(arg1:string,arg2:string,arg3:string,arg4:string,arg5:main.ImportOptions):Promise<main.ImportPlan>;
+
+export function PreflightCheck(arg1:string,arg2:string):Promise<main.PreflightReport>;
+
 export function PreviewEdgeMapping(arg1:string,arg2:string):Promise<main.PreviewEdgeMappingResult>;
 
+export function Redo():Promise<main.EditHistoryState>;
+
 export function RefreshAuth():Promise<void>;
 
+export function RefreshContextAuth(arg1:string):Promise<main.AuthRefresh>;
+
+export function RemoveKubeconfigSource(arg1:string):Promise<void>;
+
 export function RenameFlow(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
 
 export function RenameProject(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
 
+export function RestoreFlowRevision(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.ImportResult>;
+
 export function RotateNode(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function RunExpression(arg1:string,arg2:string,arg3:string):Promise<main.RunExpressionResult>;
@@ -103,6 +177,8 @@ export function SaveFile(arg1:string,arg2:string):Promise<string>;
 
 export function SaveFlowMeta(arg1:string,arg2:string,arg3:string,arg4:number,arg5:number,arg6:number):Promise<void>;
 
+export function SaveFlowRevision(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string):Promise<main.FlowRevision>;
+
 export function SavePreferences(arg1:string,arg2:string):Promise<void>;
 
 export function SaveProjectDescription(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
@@ -113,9 +189,13 @@ export function SendSignal(arg1:string,arg2:string,arg3:string,arg4:string,arg5:
 
 export function ShowAbout():Promise<void>;
 
-export function StopWatchFlowNodes():Promise<void>;
+export function StartHealthMonitor(arg1:string,arg2:string):Promise<void>;
+
+export function StopHealthMonitor():Promise<void>;
 
-export function StopWatchProjectNodes():Promise<void>;
+export function StopWatchFlowNodes(arg1:string):Promise<void>;
+
+export function StopWatchProjectNodes(arg1:string):Promise<void>;
 
 export function ToggleNodeDashboard(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<void>;
 
@@ -123,6 +203,8 @@ export function TransferNodes(arg1:string,arg2:string,arg3:main.TransferNodesReq
 
 export function UndeployFlow(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
 
+export function Undo():Promise<main.EditHistoryState>;
+
 export function UpdateEdgeConfiguration(arg1:string,arg2:string,arg3:string,arg4:string,arg5:string,arg6:string,arg7:string):Promise<void>;
 
 export function UpdateNodeComment(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
@@ -135,6 +217,6 @@ export function UpdateNodePosition(arg1:string,arg2:string,arg3:string,arg4:numb
 
 export function UpdateNodeSettings(arg1:string,arg2:string,arg3:string,arg4:main.NodeSettings):Promise<void>;
 
-export function WatchFlowNodes(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+export function WatchFlowNodes(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.WatchSubscription>;
 
-export function WatchProjectNodes(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function WatchProjectNodes(arg1:string,arg2:string,arg3:string):Promise<main.WatchSubscription>;
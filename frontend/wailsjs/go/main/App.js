@@ -2,6 +2,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddKubeconfigDirectory() {
+  return window['go']['main']['App']['AddKubeconfigDirectory']();
+}
+
+export function AddKubeconfigFile() {
+  return window['go']['main']['App']['AddKubeconfigFile']();
+}
+
 export function AddNode(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10) {
   return window['go']['main']['App']['AddNode'](arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10);
 }
@@ -14,6 +22,14 @@ export function BatchUpdateNodePositions(arg1, arg2, arg3) {
   return window['go']['main']['App']['BatchUpdateNodePositions'](arg1, arg2, arg3);
 }
 
+export function BeginEditSession(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['BeginEditSession'](arg1, arg2, arg3, arg4);
+}
+
+export function CancelImport() {
+  return window['go']['main']['App']['CancelImport']();
+}
+
 export function CheckAuthorization(arg1) {
   return window['go']['main']['App']['CheckAuthorization'](arg1);
 }
@@ -22,6 +38,10 @@ export function CheckOtelCollector(arg1, arg2) {
   return window['go']['main']['App']['CheckOtelCollector'](arg1, arg2);
 }
 
+export function CloneProject(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['CloneProject'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
 export function ConnectNodes(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8) {
   return window['go']['main']['App']['ConnectNodes'](arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8);
 }
@@ -30,6 +50,18 @@ export function ConnectToCluster(arg1) {
   return window['go']['main']['App']['ConnectToCluster'](arg1);
 }
 
+export function ConvertExportToYAML(arg1) {
+  return window['go']['main']['App']['ConvertExportToYAML'](arg1);
+}
+
+export function ConvertImportToJSON(arg1) {
+  return window['go']['main']['App']['ConvertImportToJSON'](arg1);
+}
+
+export function CopyNodes(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CopyNodes'](arg1, arg2, arg3, arg4);
+}
+
 export function CreateDashboardPage(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['CreateDashboardPage'](arg1, arg2, arg3, arg4);
 }
@@ -58,14 +90,54 @@ export function DeleteProject(arg1, arg2, arg3) {
   return window['go']['main']['App']['DeleteProject'](arg1, arg2, arg3);
 }
 
+export function DiffFlowRevisions(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['DiffFlowRevisions'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function DiffFlows(arg1, arg2) {
+  return window['go']['main']['App']['DiffFlows'](arg1, arg2);
+}
+
+export function DiffProjects(arg1, arg2) {
+  return window['go']['main']['App']['DiffProjects'](arg1, arg2);
+}
+
 export function DisconnectNodes(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['DisconnectNodes'](arg1, arg2, arg3, arg4);
 }
 
+export function DuplicateFlow(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['DuplicateFlow'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function EndEditSession() {
+  return window['go']['main']['App']['EndEditSession']();
+}
+
 export function ExportProject(arg1, arg2, arg3) {
   return window['go']['main']['App']['ExportProject'](arg1, arg2, arg3);
 }
 
+export function ExportProjectManifests(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportProjectManifests'](arg1, arg2, arg3, arg4);
+}
+
+export function ExportProjectManifestsRedacted(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['ExportProjectManifestsRedacted'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function ExportProjectManifestsToDirectory(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportProjectManifestsToDirectory'](arg1, arg2, arg3, arg4);
+}
+
+export function ExportProjectRedacted(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['ExportProjectRedacted'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function ExportProjectSelection(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportProjectSelection'](arg1, arg2, arg3, arg4);
+}
+
 export function FetchSolutionExport(arg1, arg2) {
   return window['go']['main']['App']['FetchSolutionExport'](arg1, arg2);
 }
@@ -82,6 +154,18 @@ export function GetBuildInfo() {
   return window['go']['main']['App']['GetBuildInfo']();
 }
 
+export function GetCapabilities(arg1, arg2) {
+  return window['go']['main']['App']['GetCapabilities'](arg1, arg2);
+}
+
+export function GetClusterHealth() {
+  return window['go']['main']['App']['GetClusterHealth']();
+}
+
+export function GetEditHistory() {
+  return window['go']['main']['App']['GetEditHistory']();
+}
+
 export function GetFlowForEditor(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['GetFlowForEditor'](arg1, arg2, arg3, arg4);
 }
@@ -102,6 +186,10 @@ export function GetKubeContexts() {
   return window['go']['main']['App']['GetKubeContexts']();
 }
 
+export function GetKubeconfigSources() {
+  return window['go']['main']['App']['GetKubeconfigSources']();
+}
+
 export function GetModules(arg1, arg2) {
   return window['go']['main']['App']['GetModules'](arg1, arg2);
 }
@@ -146,6 +234,10 @@ export function GetTraces(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
   return window['go']['main']['App']['GetTraces'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
 }
 
+export function GetWatchSubscriptions() {
+  return window['go']['main']['App']['GetWatchSubscriptions']();
+}
+
 export function GetWidgetPages(arg1, arg2, arg3) {
   return window['go']['main']['App']['GetWidgetPages'](arg1, arg2, arg3);
 }
@@ -154,26 +246,78 @@ export function GetWidgets(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['GetWidgets'](arg1, arg2, arg3, arg4);
 }
 
+export function ImportKubeconfig(arg1, arg2) {
+  return window['go']['main']['App']['ImportKubeconfig'](arg1, arg2);
+}
+
 export function ImportProject(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['ImportProject'](arg1, arg2, arg3, arg4);
 }
 
+export function ImportProjectWithOptions(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['ImportProjectWithOptions'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function InspectNodePort(arg1, arg2, arg3, arg4, arg5, arg6) {
   return window['go']['main']['App']['InspectNodePort'](arg1, arg2, arg3, arg4, arg5, arg6);
 }
 
+export function ListFlowRevisions(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ListFlowRevisions'](arg1, arg2, arg3, arg4);
+}
+
+export function ListImportVariables(arg1, arg2) {
+  return window['go']['main']['App']['ListImportVariables'](arg1, arg2);
+}
+
+export function LoadImportVariablesFile() {
+  return window['go']['main']['App']['LoadImportVariablesFile']();
+}
+
+export function LoadImportVariablesFromSecret(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LoadImportVariablesFromSecret'](arg1, arg2, arg3);
+}
+
 export function OpenFile() {
   return window['go']['main']['App']['OpenFile']();
 }
 
+export function PasteNodes(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['PasteNodes'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function PlanImport(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['PlanImport'](arg1, arg2, arg3, arg4);
+}
+
+export function PlanImportWithOptions(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['PlanImportWithOptions'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function PreflightCheck(arg1, arg2) {
+  return window['go']['main']['App']['PreflightCheck'](arg1, arg2);
+}
+
 export function PreviewEdgeMapping(arg1, arg2) {
   return window['go']['main']['App']['PreviewEdgeMapping'](arg1, arg2);
 }
 
+export function Redo() {
+  return window['go']['main']['App']['Redo']();
+}
+
 export function RefreshAuth() {
   return window['go']['main']['App']['RefreshAuth']();
 }
 
+export function RefreshContextAuth(arg1) {
+  return window['go']['main']['App']['RefreshContextAuth'](arg1);
+}
+
+export function RemoveKubeconfigSource(arg1) {
+  return window['go']['main']['App']['RemoveKubeconfigSource'](arg1);
+}
+
 export function RenameFlow(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['RenameFlow'](arg1, arg2, arg3, arg4);
 }
@@ -182,6 +326,10 @@ export function RenameProject(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['RenameProject'](arg1, arg2, arg3, arg4);
 }
 
+export function RestoreFlowRevision(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['RestoreFlowRevision'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function RotateNode(arg1, arg2, arg3) {
   return window['go']['main']['App']['RotateNode'](arg1, arg2, arg3);
 }
@@ -202,6 +350,10 @@ export function SaveFlowMeta(arg1, arg2, arg3, arg4, arg5, arg6) {
   return window['go']['main']['App']['SaveFlowMeta'](arg1, arg2, arg3, arg4, arg5, arg6);
 }
 
+export function SaveFlowRevision(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['SaveFlowRevision'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function SavePreferences(arg1, arg2) {
   return window['go']['main']['App']['SavePreferences'](arg1, arg2);
 }
@@ -222,12 +374,20 @@ export function ShowAbout() {
   return window['go']['main']['App']['ShowAbout']();
 }
 
-export function StopWatchFlowNodes() {
-  return window['go']['main']['App']['StopWatchFlowNodes']();
+export function StartHealthMonitor(arg1, arg2) {
+  return window['go']['main']['App']['StartHealthMonitor'](arg1, arg2);
+}
+
+export function StopHealthMonitor() {
+  return window['go']['main']['App']['StopHealthMonitor']();
+}
+
+export function StopWatchFlowNodes(arg1) {
+  return window['go']['main']['App']['StopWatchFlowNodes'](arg1);
 }
 
-export function StopWatchProjectNodes() {
-  return window['go']['main']['App']['StopWatchProjectNodes']();
+export function StopWatchProjectNodes(arg1) {
+  return window['go']['main']['App']['StopWatchProjectNodes'](arg1);
 }
 
 export function ToggleNodeDashboard(arg1, arg2, arg3, arg4) {
@@ -242,6 +402,10 @@ export function UndeployFlow(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['UndeployFlow'](arg1, arg2, arg3, arg4);
 }
 
+export function Undo() {
+  return window['go']['main']['App']['Undo']();
+}
+
 export function UpdateEdgeConfiguration(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
   return window['go']['main']['App']['UpdateEdgeConfiguration'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
 }
@@ -33,6 +33,28 @@ export namespace main {
 	        this.edges = source["edges"];
 	    }
 	}
+	export class AuthRefresh {
+	    context: string;
+	    refreshed: boolean;
+	    loginRequired: boolean;
+	    plugin?: string;
+	    loginCommand?: string;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuthRefresh(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.context = source["context"];
+	        this.refreshed = source["refreshed"];
+	        this.loginRequired = source["loginRequired"];
+	        this.plugin = source["plugin"];
+	        this.loginCommand = source["loginCommand"];
+	        this.message = source["message"];
+	    }
+	}
 	export class BuildInfo {
 	    buildTime: string;
 	    version: string;
@@ -49,6 +71,107 @@ export namespace main {
 	        this.sdkVersion = source["sdkVersion"];
 	    }
 	}
+	export class Capabilities {
+	    context: string;
+	    namespace: string;
+	    readOnly: boolean;
+	    resources: Record<string, Record<string, boolean>>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Capabilities(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.context = source["context"];
+	        this.namespace = source["namespace"];
+	        this.readOnly = source["readOnly"];
+	        this.resources = source["resources"];
+	    }
+	}
+	export class CloneMapping {
+	    source: string;
+	    target: string;
+	    title?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CloneMapping(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.target = source["target"];
+	        this.title = source["title"];
+	    }
+	}
+	export class CloneResult {
+	    project: Project;
+	    flows: CloneMapping[];
+	    nodes: CloneMapping[];
+	    import: ImportResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new CloneResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.project = this.convertValues(source["project"], Project);
+	        this.flows = this.convertValues(source["flows"], CloneMapping);
+	        this.nodes = this.convertValues(source["nodes"], CloneMapping);
+	        this.import = this.convertValues(source["import"], ImportResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ClusterHealth {
+	    context: string;
+	    namespace: string;
+	    state: string;
+	    serverVersion?: string;
+	    rttMillis: number;
+	    lastError?: string;
+	    loginCommand?: string;
+	    failures: number;
+	    checkedAt: number;
+	    lastHealthyAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ClusterHealth(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.context = source["context"];
+	        this.namespace = source["namespace"];
+	        this.state = source["state"];
+	        this.serverVersion = source["serverVersion"];
+	        this.rttMillis = source["rttMillis"];
+	        this.lastError = source["lastError"];
+	        this.loginCommand = source["loginCommand"];
+	        this.failures = source["failures"];
+	        this.checkedAt = source["checkedAt"];
+	        this.lastHealthyAt = source["lastHealthyAt"];
+	    }
+	}
 	export class ComponentInfo {
 	    name: string;
 	    module: string;
@@ -71,6 +194,115 @@ export namespace main {
 	        this.tags = source["tags"];
 	    }
 	}
+	export class DiffItem {
+	    action: string;
+	    key: string;
+	    from?: string;
+	    to?: string;
+	    title?: string;
+	    changes?: ImportFieldChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.key = source["key"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.title = source["title"];
+	        this.changes = this.convertValues(source["changes"], ImportFieldChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class DiffSource {
+	    context?: string;
+	    namespace?: string;
+	    project?: string;
+	    data?: string;
+	    flow?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.context = source["context"];
+	        this.namespace = source["namespace"];
+	        this.project = source["project"];
+	        this.data = source["data"];
+	        this.flow = source["flow"];
+	    }
+	}
+	export class DiffSummary {
+	    added: number;
+	    removed: number;
+	    changed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.added = source["added"];
+	        this.removed = source["removed"];
+	        this.changed = source["changed"];
+	    }
+	}
+	export class EditHistoryState {
+	    canUndo: boolean;
+	    canRedo: boolean;
+	    undo?: string;
+	    redo?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EditHistoryState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.canUndo = source["canUndo"];
+	        this.canRedo = source["canRedo"];
+	        this.undo = source["undo"];
+	        this.redo = source["redo"];
+	    }
+	}
+	export class ExportSelection {
+	    flows: string[];
+	    pages: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportSelection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.flows = source["flows"];
+	        this.pages = source["pages"];
+	    }
+	}
 	export class Flow {
 	    name: string;
 	    resourceName: string;
@@ -89,6 +321,30 @@ export namespace main {
 	        this.graph = source["graph"];
 	    }
 	}
+	export class FlowRevision {
+	    id: string;
+	    flow: string;
+	    author: string;
+	    message?: string;
+	    createdAt: string;
+	    nodes: number;
+	    edges: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FlowRevision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.flow = source["flow"];
+	        this.author = source["author"];
+	        this.message = source["message"];
+	        this.createdAt = source["createdAt"];
+	        this.nodes = source["nodes"];
+	        this.edges = source["edges"];
+	    }
+	}
 	export class ProjectInfo {
 	    name: string;
 	    resourceName: string;
@@ -156,11 +412,264 @@ export namespace main {
 		}
 	}
 	
+	export class ImportChange {
+	    kind: string;
+	    name: string;
+	    title?: string;
+	    action: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.name = source["name"];
+	        this.title = source["title"];
+	        this.action = source["action"];
+	    }
+	}
+	export class ImportResult {
+	    applied: ImportChange[];
+	    reverted?: ImportChange[];
+	    rolledBack: boolean;
+	    rollbackErrors?: string[];
+	    warnings?: string[];
+	    summary: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.applied = this.convertValues(source["applied"], ImportChange);
+	        this.reverted = this.convertValues(source["reverted"], ImportChange);
+	        this.rolledBack = source["rolledBack"];
+	        this.rollbackErrors = source["rollbackErrors"];
+	        this.warnings = source["warnings"];
+	        this.summary = source["summary"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ImportFieldChange {
+	    field: string;
+	    before?: string;
+	    after?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportFieldChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	    }
+	}
+	export class ImportPlanItem {
+	    action: string;
+	    name?: string;
+	    title?: string;
+	    id?: string;
+	    parent?: string;
+	    reason?: string;
+	    changes?: ImportFieldChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPlanItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.name = source["name"];
+	        this.title = source["title"];
+	        this.id = source["id"];
+	        this.parent = source["parent"];
+	        this.reason = source["reason"];
+	        this.changes = this.convertValues(source["changes"], ImportFieldChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ImportPlanSummary {
+	    create: number;
+	    update: number;
+	    delete: number;
+	    skip: number;
+	    unchanged: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPlanSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.create = source["create"];
+	        this.update = source["update"];
+	        this.delete = source["delete"];
+	        this.skip = source["skip"];
+	        this.unchanged = source["unchanged"];
+	    }
+	}
+	export class ImportPlan {
+	    project?: ImportPlanItem;
+	    flows: ImportPlanItem[];
+	    nodes: ImportPlanItem[];
+	    edges: ImportPlanItem[];
+	    portConfigs: ImportPlanItem[];
+	    pages: ImportPlanItem[];
+	    widgets: ImportPlanItem[];
+	    scenarios: ImportPlanItem[];
+	    warnings?: string[];
+	    summary: ImportPlanSummary;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPlan(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.project = this.convertValues(source["project"], ImportPlanItem);
+	        this.flows = this.convertValues(source["flows"], ImportPlanItem);
+	        this.nodes = this.convertValues(source["nodes"], ImportPlanItem);
+	        this.edges = this.convertValues(source["edges"], ImportPlanItem);
+	        this.portConfigs = this.convertValues(source["portConfigs"], ImportPlanItem);
+	        this.pages = this.convertValues(source["pages"], ImportPlanItem);
+	        this.widgets = this.convertValues(source["widgets"], ImportPlanItem);
+	        this.scenarios = this.convertValues(source["scenarios"], ImportPlanItem);
+	        this.warnings = source["warnings"];
+	        this.summary = this.convertValues(source["summary"], ImportPlanSummary);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ImportSelection {
+	    flows: string[];
+	    pages: string[];
+	    scenarios: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportSelection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.flows = source["flows"];
+	        this.pages = source["pages"];
+	        this.scenarios = source["scenarios"];
+	    }
+	}
+	export class ImportVariable {
+	    name: string;
+	    locations: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.locations = source["locations"];
+	    }
+	}
+	export class ImportOptions {
+	    selection?: ImportSelection;
+	    variables?: Record<string, string>;
+	    substituteVariables?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.selection = this.convertValues(source["selection"], ImportSelection);
+	        this.variables = source["variables"];
+	        this.substituteVariables = source["substituteVariables"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class KubeContext {
 	    name: string;
 	    cluster: string;
 	    user: string;
 	    current: boolean;
+	    source: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new KubeContext(source);
@@ -172,6 +681,27 @@ export namespace main {
 	        this.cluster = source["cluster"];
 	        this.user = source["user"];
 	        this.current = source["current"];
+	        this.source = source["source"];
+	    }
+	}
+	export class KubeconfigSource {
+	    path: string;
+	    kind: string;
+	    isDir: boolean;
+	    contexts: string[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new KubeconfigSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	        this.isDir = source["isDir"];
+	        this.contexts = source["contexts"];
+	        this.error = source["error"];
 	    }
 	}
 	export class ModuleComponent {
@@ -277,6 +807,67 @@ export namespace main {
 	        this.lastNamespace = source["lastNamespace"];
 	    }
 	}
+	export class PreflightItem {
+	    category: string;
+	    name: string;
+	    status: string;
+	    message?: string;
+	    remediation?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PreflightItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.category = source["category"];
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.message = source["message"];
+	        this.remediation = source["remediation"];
+	    }
+	}
+	export class PreflightReport {
+	    context: string;
+	    namespace: string;
+	    ready: boolean;
+	    failures: number;
+	    warnings: number;
+	    items: PreflightItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PreflightReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.context = source["context"];
+	        this.namespace = source["namespace"];
+	        this.ready = source["ready"];
+	        this.failures = source["failures"];
+	        this.warnings = source["warnings"];
+	        this.items = this.convertValues(source["items"], PreflightItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class PreviewEdgeMappingResult {
 	    result: string;
 	    errors: string[];
@@ -328,6 +919,49 @@ export namespace main {
 	    }
 	}
 	
+	export class ProjectDiff {
+	    flows: DiffItem[];
+	    nodes: DiffItem[];
+	    edges: DiffItem[];
+	    pages: DiffItem[];
+	    widgets: DiffItem[];
+	    scenarios: DiffItem[];
+	    summary: DiffSummary;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.flows = this.convertValues(source["flows"], DiffItem);
+	        this.nodes = this.convertValues(source["nodes"], DiffItem);
+	        this.edges = this.convertValues(source["edges"], DiffItem);
+	        this.pages = this.convertValues(source["pages"], DiffItem);
+	        this.widgets = this.convertValues(source["widgets"], DiffItem);
+	        this.scenarios = this.convertValues(source["scenarios"], DiffItem);
+	        this.summary = this.convertValues(source["summary"], DiffSummary);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class ProjectStats {
 	    widgetsCount: number;
 	    flowsCount: number;
@@ -344,6 +978,155 @@ export namespace main {
 	        this.nodesCount = source["nodesCount"];
 	    }
 	}
+	export class RedactionOptions {
+	    schemaSecrets: boolean;
+	    keyNames: boolean;
+	    extraKeyNames: string[];
+	    highEntropy: boolean;
+	    minEntropyLength: number;
+	    entropyThreshold: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RedactionOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.schemaSecrets = source["schemaSecrets"];
+	        this.keyNames = source["keyNames"];
+	        this.extraKeyNames = source["extraKeyNames"];
+	        this.highEntropy = source["highEntropy"];
+	        this.minEntropyLength = source["minEntropyLength"];
+	        this.entropyThreshold = source["entropyThreshold"];
+	    }
+	}
+	export class Redaction {
+	    placeholder: string;
+	    node?: string;
+	    edge?: string;
+	    port?: string;
+	    path: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Redaction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.placeholder = source["placeholder"];
+	        this.node = source["node"];
+	        this.edge = source["edge"];
+	        this.port = source["port"];
+	        this.path = source["path"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class RedactionReport {
+	    redactions: Redaction[];
+	    placeholders: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RedactionReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.redactions = this.convertValues(source["redactions"], Redaction);
+	        this.placeholders = source["placeholders"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class RedactedExport {
+	    data: string;
+	    report: RedactionReport;
+	
+	    static createFrom(source: any = {}) {
+	        return new RedactedExport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.data = source["data"];
+	        this.report = this.convertValues(source["report"], RedactionReport);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class RevisionDiffItem {
+	    kind: string;
+	    action: string;
+	    id: string;
+	    title?: string;
+	    changes?: ImportFieldChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RevisionDiffItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.action = source["action"];
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.changes = this.convertValues(source["changes"], ImportFieldChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class RunExpressionResult {
 	    result: string;
 	    validSchema: boolean;
@@ -444,6 +1227,58 @@ export namespace main {
 	        this.nodeIds = source["nodeIds"];
 	    }
 	}
+	export class WatchSubscription {
+	    id: string;
+	    event: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchSubscription(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.event = source["event"];
+	    }
+	}
+	export class WatchSubscriptionStatus {
+	    id: string;
+	    event: string;
+	    kind: string;
+	    context: string;
+	    namespace: string;
+	    project: string;
+	    flow?: string;
+	    subscribers: number;
+	    healthy: boolean;
+	    state: string;
+	    attempt: number;
+	    error?: string;
+	    createdAt: number;
+	    lastEventAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchSubscriptionStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.event = source["event"];
+	        this.kind = source["kind"];
+	        this.context = source["context"];
+	        this.namespace = source["namespace"];
+	        this.project = source["project"];
+	        this.flow = source["flow"];
+	        this.subscribers = source["subscribers"];
+	        this.healthy = source["healthy"];
+	        this.state = source["state"];
+	        this.attempt = source["attempt"];
+	        this.error = source["error"];
+	        this.createdAt = source["createdAt"];
+	        this.lastEventAt = source["lastEventAt"];
+	    }
+	}
 	export class Widget {
 	    id: string;
 	    title: string;
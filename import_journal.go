@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	importActionCreated = "created"
+	importActionUpdated = "updated"
+	importActionDeleted = "deleted"
+)
+
+// ImportChange is a single resource created or changed by an import.
+type ImportChange struct {
+	Kind   string `json:"kind"` // project, flow, node, page or scenario
+	Name   string `json:"name"`
+	Title  string `json:"title,omitempty"`
+	Action string `json:"action"` // created, updated or deleted
+}
+
+func (c ImportChange) String() string {
+	s := fmt.Sprintf("%s %s %s", c.Action, c.Kind, c.Name)
+	if c.Title != "" && c.Title != c.Name {
+		s += fmt.Sprintf(" (%s)", c.Title)
+	}
+	return s
+}
+
+// ImportResult reports what an import applied and, when it failed, what was reverted.
+type ImportResult struct {
+	Applied        []ImportChange `json:"applied"`
+	Reverted       []ImportChange `json:"reverted,omitempty"`
+	RolledBack     bool           `json:"rolledBack"`
+	RollbackErrors []string       `json:"rollbackErrors,omitempty"`
+	Warnings       []string       `json:"warnings,omitempty"`
+	Summary        string         `json:"summary"`
+}
+
+type importJournalEntry struct {
+	change ImportChange
+	undo   func(ctx context.Context) error
+}
+
+// importJournal records every change an import makes together with how to undo it,
+// so a failed or cancelled import can put the cluster back to its previous state.
+type importJournal struct {
+	mu      sync.Mutex
+	entries []importJournalEntry
+}
+
+// record adds a change and the function that reverts it.
+func (j *importJournal) record(change ImportChange, undo func(ctx context.Context) error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = append(j.entries, importJournalEntry{change: change, undo: undo})
+}
+
+// applied returns all recorded changes in the order they were made.
+func (j *importJournal) applied() []ImportChange {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	changes := make([]ImportChange, 0, len(j.entries))
+	for _, e := range j.entries {
+		changes = append(changes, e.change)
+	}
+	return changes
+}
+
+// rollback undoes recorded changes in reverse order. It keeps going on errors
+// so as much as possible is reverted.
+func (j *importJournal) rollback(ctx context.Context, progress func(string)) (reverted []ImportChange, errs []string) {
+	j.mu.Lock()
+	entries := j.entries
+	j.entries = nil
+	j.mu.Unlock()
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		progress(fmt.Sprintf("Rolling back: %s (%d left)", e.change, i))
+		if err := e.undo(ctx); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", e.change, err))
+			continue
+		}
+		reverted = append(reverted, e.change)
+	}
+	return reverted, errs
+}
+
+// rollbackReport formats reverted changes and rollback errors for an error message.
+func (r *ImportResult) rollbackReport() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("rolled back %d of %d changes", len(r.Reverted), len(r.Applied)))
+	for _, c := range r.Reverted {
+		b.WriteString("\n  reverted " + c.String())
+	}
+	for _, e := range r.RollbackErrors {
+		b.WriteString("\n  failed to revert " + e)
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestImportJournalRollback(t *testing.T) {
+	tests := []struct {
+		name         string
+		changes      []string
+		failing      map[string]bool
+		wantUndone   []string
+		wantReverted []string
+		wantErrs     []string
+	}{
+		{
+			name: "empty journal",
+		},
+		{
+			name:         "undoes in reverse order",
+			changes:      []string{"flow-a", "node-a", "node-b"},
+			wantUndone:   []string{"node-b", "node-a", "flow-a"},
+			wantReverted: []string{"node-b", "node-a", "flow-a"},
+		},
+		{
+			name:         "keeps going after a failed undo",
+			changes:      []string{"flow-a", "node-a", "node-b"},
+			failing:      map[string]bool{"node-a": true},
+			wantUndone:   []string{"node-b", "node-a", "flow-a"},
+			wantReverted: []string{"node-b", "flow-a"},
+			wantErrs:     []string{"created node node-a: conflict"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &importJournal{}
+			var undone []string
+			for _, name := range tt.changes {
+				name := name
+				j.record(ImportChange{Kind: "node", Name: name, Action: importActionCreated}, func(context.Context) error {
+					undone = append(undone, name)
+					if tt.failing[name] {
+						return errors.New("conflict")
+					}
+					return nil
+				})
+			}
+
+			var progress int
+			reverted, errs := j.rollback(context.Background(), func(string) { progress++ })
+
+			var revertedNames []string
+			for _, c := range reverted {
+				revertedNames = append(revertedNames, c.Name)
+			}
+			if !reflect.DeepEqual(undone, tt.wantUndone) {
+				t.Errorf("undone = %v, want %v", undone, tt.wantUndone)
+			}
+			if !reflect.DeepEqual(revertedNames, tt.wantReverted) {
+				t.Errorf("reverted = %v, want %v", revertedNames, tt.wantReverted)
+			}
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("errors = %v, want %v", errs, tt.wantErrs)
+			}
+			if progress != len(tt.changes) {
+				t.Errorf("progress reported %d times, want %d", progress, len(tt.changes))
+			}
+			if len(j.applied()) != 0 {
+				t.Error("journal not emptied by rollback")
+			}
+			if reverted, _ := j.rollback(context.Background(), func(string) {}); len(reverted) != 0 {
+				t.Error("second rollback undid changes again")
+			}
+		})
+	}
+}
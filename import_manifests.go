@@ -0,0 +1,341 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/utils"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ConvertImportToJSON returns import data in any supported format as project export JSON,
+// without validating it.
+func (a *App) ConvertImportToJSON(data string) (string, error) {
+	importData, err := decodeProjectImport(data)
+	if err != nil {
+		return "", fmt.Errorf("invalid import data: %v", err)
+	}
+	out, err := json.MarshalIndent(importData, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// decodeProjectImport decodes a project export as JSON or YAML, or converts a multi-document
+// YAML bundle of TinyProject, TinyFlow, TinyNode, TinyWidgetPage and TinyScenario manifests,
+// as written by ExportProjectManifests or kubectl get -o yaml, into one.
+func decodeProjectImport(data string) (*utils.ProjectExport, error) {
+	var importData utils.ProjectExport
+	if strings.HasPrefix(strings.TrimSpace(data), "{") {
+		if err := json.Unmarshal([]byte(data), &importData); err != nil {
+			return nil, err
+		}
+		return &importData, nil
+	}
+
+	var docs []map[string]interface{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(data), 4096)
+	for {
+		var doc map[string]interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+
+	if len(docs) == 0 {
+		return nil, errors.New("no data")
+	}
+	if _, ok := docs[0]["kind"]; ok {
+		return manifestsToExport(docs)
+	}
+	if len(docs) > 1 {
+		return nil, errors.New("expected a single project export document")
+	}
+	if err := convertViaJSON(docs[0], &importData); err != nil {
+		return nil, err
+	}
+	return &importData, nil
+}
+
+// manifestsToExport builds a project export from resource manifests. Nodes are converted from
+// their spec, so manifests stripped of status import the same as ones dumped from a cluster.
+func manifestsToExport(docs []map[string]interface{}) (*utils.ProjectExport, error) {
+	var (
+		project   *v1alpha1.TinyProject
+		flows     []v1alpha1.TinyFlow
+		nodes     []v1alpha1.TinyNode
+		pages     []v1alpha1.TinyWidgetPage
+		scenarios []v1alpha1.TinyScenario
+	)
+
+	for len(docs) > 0 {
+		doc := docs[0]
+		docs = docs[1:]
+
+		apiVersion, _ := doc["apiVersion"].(string)
+		kind, _ := doc["kind"].(string)
+		if strings.HasSuffix(kind, "List") {
+			// kubectl get -o yaml wraps resources in a list
+			items, _ := doc["items"].([]interface{})
+			for _, item := range items {
+				if m, ok := item.(map[string]interface{}); ok {
+					docs = append(docs, m)
+				}
+			}
+			continue
+		}
+		if group, _, _ := strings.Cut(apiVersion, "/"); group != v1alpha1.GroupVersion.Group {
+			return nil, fmt.Errorf("unsupported manifest %s %s", apiVersion, kind)
+		}
+
+		var err error
+		switch kind {
+		case "TinyProject":
+			project = &v1alpha1.TinyProject{}
+			err = convertViaJSON(doc, project)
+		case "TinyFlow":
+			var flow v1alpha1.TinyFlow
+			err = convertViaJSON(doc, &flow)
+			flows = append(flows, flow)
+		case "TinyNode":
+			var node v1alpha1.TinyNode
+			err = convertViaJSON(doc, &node)
+			nodes = append(nodes, node)
+		case "TinyWidgetPage":
+			var page v1alpha1.TinyWidgetPage
+			err = convertViaJSON(doc, &page)
+			pages = append(pages, page)
+		case "TinyScenario":
+			var scenario v1alpha1.TinyScenario
+			err = convertViaJSON(doc, &scenario)
+			scenarios = append(scenarios, scenario)
+		default:
+			return nil, fmt.Errorf("unsupported manifest kind %q", kind)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s manifest: %w", kind, err)
+		}
+	}
+
+	export := &utils.ProjectExport{
+		Version:   utils.CurrentExportVersion,
+		TinyFlows: []utils.ExportFlow{},
+		Elements:  []map[string]interface{}{},
+	}
+	if project != nil {
+		export.Description = project.Spec.Description
+	}
+
+	for _, flow := range flows {
+		name := flow.Annotations[v1alpha1.FlowDescriptionAnnotation]
+		if name == "" {
+			name = flow.Name
+		}
+		export.TinyFlows = append(export.TinyFlows, utils.ExportFlow{
+			ResourceName: flow.Name,
+			Name:         name,
+		})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	nodesMap := make(map[string]v1alpha1.TinyNode, len(nodes))
+	for _, node := range nodes {
+		nodesMap[node.Name] = node
+		export.Elements = append(export.Elements, manifestNodeElement(node))
+	}
+	for _, node := range nodes {
+		export.Elements = append(export.Elements, manifestEdgeElements(node, nodesMap)...)
+	}
+
+	for _, page := range pages {
+		sortIdx, _ := strconv.Atoi(page.Annotations[v1alpha1.PageSortIdxAnnotation])
+		title := page.Annotations[v1alpha1.PageTitleAnnotation]
+		if title == "" {
+			title = page.Name
+		}
+		widgets := []utils.ExportWidget{}
+		for _, w := range page.Spec.Widgets {
+			widgets = append(widgets, utils.ExportWidget{
+				Port:        w.Port,
+				Name:        w.Name,
+				GridX:       w.GridX,
+				GridY:       w.GridY,
+				GridW:       w.GridW,
+				GridH:       w.GridH,
+				SchemaPatch: w.SchemaPatch,
+			})
+		}
+		export.Pages = append(export.Pages, utils.ExportPage{
+			Name:    page.Name,
+			Title:   title,
+			SortIdx: sortIdx,
+			Widgets: widgets,
+		})
+	}
+
+	for _, scenario := range scenarios {
+		name := scenario.Annotations[v1alpha1.ScenarioNameAnnotation]
+		if name == "" {
+			name = scenario.Name
+		}
+		var ports []utils.ExportScenarioPortData
+		for _, p := range scenario.Spec.Ports {
+			ports = append(ports, utils.ExportScenarioPortData{
+				Port: p.Port,
+				Data: p.Data,
+			})
+		}
+		export.Scenarios = append(export.Scenarios, utils.ExportScenario{
+			Name:  name,
+			Ports: ports,
+		})
+	}
+
+	return export, nil
+}
+
+// manifestNodeElement returns the export element of a node, with a handle for every port
+// it has settings or incoming edge configurations for and for every port it has edges from.
+func manifestNodeElement(node v1alpha1.TinyNode) map[string]interface{} {
+	handles := []interface{}{}
+	seen := make(map[string]bool)
+	for _, p := range node.Spec.Ports {
+		// Like ExportProject, leave out _control port configuration — it holds runtime state
+		if p.From != "" || p.Port == v1alpha1.ControlPort {
+			continue
+		}
+		handle := map[string]interface{}{
+			"id":   p.Port,
+			"type": "target",
+		}
+		if v := decodeRawJSON(p.Configuration); v != nil {
+			handle["configuration"] = v
+		}
+		if v := decodeRawJSON(p.Schema); v != nil {
+			handle["schema"] = v
+		}
+		handles = append(handles, handle)
+		seen[p.Port] = true
+	}
+	for _, p := range node.Spec.Ports {
+		if p.From == "" || seen[p.Port] {
+			continue
+		}
+		seen[p.Port] = true
+		handles = append(handles, map[string]interface{}{
+			"id":   p.Port,
+			"type": "target",
+		})
+	}
+
+	sources := make(map[string]bool)
+	for _, e := range node.Spec.Edges {
+		if sources[e.Port] {
+			continue
+		}
+		sources[e.Port] = true
+		handles = append(handles, map[string]interface{}{
+			"id":   e.Port,
+			"type": "source",
+		})
+	}
+
+	posX, _ := strconv.Atoi(node.Annotations[v1alpha1.ComponentPosXAnnotation])
+	posY, _ := strconv.Atoi(node.Annotations[v1alpha1.ComponentPosYAnnotation])
+	spin, _ := strconv.Atoi(node.Annotations[v1alpha1.ComponentPosSpinAnnotation])
+
+	data := map[string]interface{}{
+		"module":    node.Spec.Module,
+		"component": node.Spec.Component,
+		"label":     node.Annotations[v1alpha1.NodeLabelAnnotation],
+		"spin":      float64(spin),
+		"handles":   handles,
+	}
+	if node.Labels[v1alpha1.DashboardLabel] == "true" {
+		data["dashboard"] = "true"
+	}
+	if shared := node.Annotations[v1alpha1.SharedWithFlowsAnnotation]; shared != "" {
+		data["shared_with_flows"] = shared
+	}
+
+	return map[string]interface{}{
+		"id":       node.Name,
+		"type":     "tinyNode",
+		"flow":     node.Labels[v1alpha1.FlowNameLabel],
+		"position": map[string]interface{}{"x": float64(posX), "y": float64(posY)},
+		"data":     data,
+	}
+}
+
+// manifestEdgeElements returns the export elements of the edges of a node. Edge configurations
+// are stored on the target node as port configurations from the source port.
+func manifestEdgeElements(node v1alpha1.TinyNode, nodes map[string]v1alpha1.TinyNode) []map[string]interface{} {
+	var elements []map[string]interface{}
+	for _, e := range node.Spec.Edges {
+		targetID, targetHandle, _ := strings.Cut(e.To, ":")
+		flow := e.FlowID
+		if flow == "" {
+			flow = node.Labels[v1alpha1.FlowNameLabel]
+		}
+
+		data := map[string]interface{}{}
+		from := node.Name + ":" + e.Port
+		for _, p := range nodes[targetID].Spec.Ports {
+			if p.From != from || p.Port != targetHandle {
+				continue
+			}
+			if v := decodeRawJSON(p.Configuration); v != nil {
+				data["configuration"] = v
+			}
+			if v := decodeRawJSON(p.Schema); v != nil {
+				data["schema"] = v
+			}
+		}
+
+		elements = append(elements, map[string]interface{}{
+			"id":           e.ID,
+			"type":         "tinyEdge",
+			"source":       node.Name,
+			"sourceHandle": e.Port,
+			"target":       targetID,
+			"targetHandle": targetHandle,
+			"flow":         flow,
+			"data":         data,
+		})
+	}
+	return elements
+}
+
+// convertViaJSON decodes a generic document into a typed value through its JSON encoding.
+func convertViaJSON(doc map[string]interface{}, into interface{}) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
+
+// decodeRawJSON returns stored JSON as a generic value, or nil if there's none.
+func decodeRawJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// manifestTestDoc returns a resource as the generic document a manifest decodes to.
+func manifestTestDoc(t *testing.T, kind string, obj interface{}) map[string]interface{} {
+	t.Helper()
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	doc["apiVersion"] = v1alpha1.GroupVersion.String()
+	doc["kind"] = kind
+	return doc
+}
+
+func TestManifestsToExport(t *testing.T) {
+	project := v1alpha1.TinyProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo"},
+		Spec:       v1alpha1.TinyProjectSpec{Description: "Demo project"},
+	}
+	flow := v1alpha1.TinyFlow{
+		ObjectMeta: metav1.ObjectMeta{Name: "flow-a", Annotations: map[string]string{v1alpha1.FlowDescriptionAnnotation: "Main"}},
+	}
+	unnamedFlow := v1alpha1.TinyFlow{ObjectMeta: metav1.ObjectMeta{Name: "flow-b"}}
+	ticker := v1alpha1.TinyNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "ticker",
+			Labels: map[string]string{v1alpha1.FlowNameLabel: "flow-a"},
+			Annotations: map[string]string{
+				v1alpha1.NodeLabelAnnotation:     "Ticker",
+				v1alpha1.ComponentPosXAnnotation: "10",
+				v1alpha1.ComponentPosYAnnotation: "20",
+			},
+		},
+		Spec: v1alpha1.TinyNodeSpec{
+			Module:    "common-module",
+			Component: "ticker",
+			Ports: []v1alpha1.TinyNodePortConfig{
+				{Port: "settings", Configuration: []byte(`{"delay":1000}`)},
+				{Port: v1alpha1.ControlPort, Configuration: []byte(`{"start":true}`)},
+			},
+			Edges: []v1alpha1.TinyNodeEdge{{ID: "e1", Port: "out", To: "debug:in", FlowID: "flow-a"}},
+		},
+	}
+	debug := v1alpha1.TinyNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "debug",
+			Labels: map[string]string{v1alpha1.FlowNameLabel: "flow-a"},
+		},
+		Spec: v1alpha1.TinyNodeSpec{
+			Module:    "common-module",
+			Component: "debug",
+			Ports: []v1alpha1.TinyNodePortConfig{
+				{Port: "in", From: "ticker:out", Configuration: []byte(`{"context":"{{$}}"}`), FlowID: "flow-a"},
+			},
+		},
+	}
+	page := v1alpha1.TinyWidgetPage{
+		ObjectMeta: metav1.ObjectMeta{Name: "page-1", Annotations: map[string]string{
+			v1alpha1.PageTitleAnnotation:   "Overview",
+			v1alpha1.PageSortIdxAnnotation: "2",
+		}},
+		Spec: v1alpha1.TinyWidgetPageSpec{Widgets: []v1alpha1.TinyWidget{{Name: "Delay", Port: "ticker:settings", GridW: 4}}},
+	}
+	scenario := v1alpha1.TinyScenario{
+		ObjectMeta: metav1.ObjectMeta{Name: "scenario-1", Annotations: map[string]string{v1alpha1.ScenarioNameAnnotation: "Smoke"}},
+	}
+
+	elementIDs := func(export *utils.ProjectExport) []string {
+		var ids []string
+		for _, elem := range export.Elements {
+			id, _ := elem["id"].(string)
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name    string
+		docs    func(t *testing.T) []map[string]interface{}
+		wantErr bool
+		check   func(t *testing.T, export *utils.ProjectExport)
+	}{
+		{
+			name: "project bundle",
+			docs: func(t *testing.T) []map[string]interface{} {
+				return []map[string]interface{}{
+					manifestTestDoc(t, "TinyProject", project),
+					manifestTestDoc(t, "TinyFlow", flow),
+					manifestTestDoc(t, "TinyFlow", unnamedFlow),
+					manifestTestDoc(t, "TinyNode", ticker),
+					manifestTestDoc(t, "TinyNode", debug),
+					manifestTestDoc(t, "TinyWidgetPage", page),
+					manifestTestDoc(t, "TinyScenario", scenario),
+				}
+			},
+			check: func(t *testing.T, export *utils.ProjectExport) {
+				if export.Description != "Demo project" {
+					t.Errorf("description = %q", export.Description)
+				}
+				wantFlows := []utils.ExportFlow{{ResourceName: "flow-a", Name: "Main"}, {ResourceName: "flow-b", Name: "flow-b"}}
+				if !reflect.DeepEqual(export.TinyFlows, wantFlows) {
+					t.Errorf("flows = %+v, want %+v", export.TinyFlows, wantFlows)
+				}
+				// nodes sorted by name, then edges
+				if got, want := elementIDs(export), []string{"debug", "ticker", "e1"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("elements = %v, want %v", got, want)
+				}
+				if len(export.Pages) != 1 || export.Pages[0].Title != "Overview" || export.Pages[0].SortIdx != 2 || len(export.Pages[0].Widgets) != 1 {
+					t.Errorf("pages = %+v", export.Pages)
+				}
+				if len(export.Scenarios) != 1 || export.Scenarios[0].Name != "Smoke" {
+					t.Errorf("scenarios = %+v", export.Scenarios)
+				}
+			},
+		},
+		{
+			name: "node handles and edge configuration",
+			docs: func(t *testing.T) []map[string]interface{} {
+				return []map[string]interface{}{manifestTestDoc(t, "TinyNode", ticker), manifestTestDoc(t, "TinyNode", debug)}
+			},
+			check: func(t *testing.T, export *utils.ProjectExport) {
+				tickerElem := export.Elements[1]
+				data := tickerElem["data"].(map[string]interface{})
+				wantHandles := []interface{}{
+					map[string]interface{}{"id": "settings", "type": "target", "configuration": map[string]interface{}{"delay": float64(1000)}},
+					map[string]interface{}{"id": "out", "type": "source"},
+				}
+				if !reflect.DeepEqual(data["handles"], wantHandles) {
+					t.Errorf("ticker handles = %v, want %v", data["handles"], wantHandles)
+				}
+				if data["label"] != "Ticker" || tickerElem["flow"] != "flow-a" {
+					t.Errorf("ticker element = %v", tickerElem)
+				}
+				if pos := tickerElem["position"].(map[string]interface{}); pos["x"] != float64(10) || pos["y"] != float64(20) {
+					t.Errorf("ticker position = %v", pos)
+				}
+
+				debugHandles := export.Elements[0]["data"].(map[string]interface{})["handles"]
+				if want := []interface{}{map[string]interface{}{"id": "in", "type": "target"}}; !reflect.DeepEqual(debugHandles, want) {
+					t.Errorf("debug handles = %v, want %v", debugHandles, want)
+				}
+
+				edge := export.Elements[2]
+				if edge["source"] != "ticker" || edge["sourceHandle"] != "out" || edge["target"] != "debug" || edge["targetHandle"] != "in" || edge["flow"] != "flow-a" {
+					t.Errorf("edge = %v", edge)
+				}
+				wantData := map[string]interface{}{"configuration": map[string]interface{}{"context": "{{$}}"}}
+				if !reflect.DeepEqual(edge["data"], wantData) {
+					t.Errorf("edge data = %v, want %v", edge["data"], wantData)
+				}
+			},
+		},
+		{
+			name: "kubectl list output",
+			docs: func(t *testing.T) []map[string]interface{} {
+				return []map[string]interface{}{{
+					"apiVersion": "v1",
+					"kind":       "List",
+					"items": []interface{}{
+						manifestTestDoc(t, "TinyFlow", flow),
+						manifestTestDoc(t, "TinyNode", debug),
+					},
+				}}
+			},
+			check: func(t *testing.T, export *utils.ProjectExport) {
+				if len(export.TinyFlows) != 1 {
+					t.Errorf("flows = %+v", export.TinyFlows)
+				}
+				if got, want := elementIDs(export), []string{"debug"}; !reflect.DeepEqual(got, want) {
+					t.Errorf("elements = %v, want %v", got, want)
+				}
+			},
+		},
+		{
+			name: "other API group",
+			docs: func(t *testing.T) []map[string]interface{} {
+				return []map[string]interface{}{{"apiVersion": "apps/v1", "kind": "Deployment"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown kind",
+			docs: func(t *testing.T) []map[string]interface{} {
+				return []map[string]interface{}{manifestTestDoc(t, "TinyModule", map[string]interface{}{})}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export, err := manifestsToExport(tt.docs(t))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, export)
+		})
+	}
+}
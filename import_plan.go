@@ -0,0 +1,458 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+)
+
+// Plan actions
+const (
+	planActionCreate    = "create"
+	planActionUpdate    = "update"
+	planActionDelete    = "delete"
+	planActionSkip      = "skip"
+	planActionUnchanged = "unchanged"
+)
+
+// ImportFieldChange is a single field that an import would change on an existing resource.
+type ImportFieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// ImportPlanItem is one resource an import would create, update, delete or skip.
+type ImportPlanItem struct {
+	Action  string              `json:"action"` // create, update, delete, skip or unchanged
+	Name    string              `json:"name,omitempty"`
+	Title   string              `json:"title,omitempty"`
+	ID      string              `json:"id,omitempty"`     // id in the import data
+	Parent  string              `json:"parent,omitempty"` // node or page the item belongs to
+	Reason  string              `json:"reason,omitempty"`
+	Changes []ImportFieldChange `json:"changes,omitempty"`
+}
+
+// ImportPlanSummary counts plan items by action.
+type ImportPlanSummary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Delete    int `json:"delete"`
+	Skip      int `json:"skip"`
+	Unchanged int `json:"unchanged"`
+}
+
+// ImportPlan is a dry run of ImportProject: everything it would change, without touching the cluster.
+type ImportPlan struct {
+	Project     *ImportPlanItem   `json:"project,omitempty"`
+	Flows       []ImportPlanItem  `json:"flows"`
+	Nodes       []ImportPlanItem  `json:"nodes"`
+	Edges       []ImportPlanItem  `json:"edges"`
+	PortConfigs []ImportPlanItem  `json:"portConfigs"`
+	Pages       []ImportPlanItem  `json:"pages"`
+	Widgets     []ImportPlanItem  `json:"widgets"`
+	Scenarios   []ImportPlanItem  `json:"scenarios"`
+	Warnings    []string          `json:"warnings,omitempty"`
+	Summary     ImportPlanSummary `json:"summary"`
+}
+
+// PlanImport returns what ImportProject would do with the same data, without changing anything.
+func (a *App) PlanImport(contextName string, namespace string, projectName string, jsonData string) (*ImportPlan, error) {
+	return a.planImport(contextName, namespace, projectName, jsonData, ImportOptions{})
+}
+
+// planImport plans an import of all of the JSON data, or only the selection if one is given.
+func (a *App) planImport(contextName string, namespace string, projectName string, jsonData string, options ImportOptions) (*ImportPlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	importData, err := a.parseProjectImport(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr, err := a.getManager(contextName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &projectImporter{
+		app:         a,
+		mgr:         mgr,
+		namespace:   namespace,
+		projectName: projectName,
+		data:        importData,
+		journal:     &importJournal{},
+		progress:    func(string) {},
+
+		pruneFlowEdges: options.pruneFlowEdges,
+	}
+	if options.Selection != nil {
+		if err := imp.selectOnly(*options.Selection); err != nil {
+			return nil, err
+		}
+	}
+	if options.SubstituteVariables {
+		if missing := imp.substituteVariables(options.Variables); len(missing) > 0 {
+			imp.warnings = append(imp.warnings, "missing values for import variables: "+strings.Join(missing, ", "))
+		}
+	}
+
+	plan := &ImportPlan{}
+	if err := imp.plan(ctx, plan); err != nil {
+		return nil, err
+	}
+	plan.Warnings = imp.warnings
+	plan.summarize()
+	return plan, nil
+}
+
+// plan walks the same steps as run, recording changes in the plan instead of applying them.
+func (imp *projectImporter) plan(ctx context.Context, plan *ImportPlan) error {
+	if imp.data.Description != "" {
+		project, err := imp.mgr.GetProject(ctx, imp.projectName, imp.namespace)
+		if err != nil {
+			return fmt.Errorf("unable to get project: %w", err)
+		}
+		item := ImportPlanItem{Action: planActionUnchanged, Name: imp.projectName}
+		if project.Spec.Description != imp.data.Description {
+			item.Action = planActionUpdate
+			item.Changes = []ImportFieldChange{{Field: "description", Before: project.Spec.Description, After: imp.data.Description}}
+		}
+		plan.Project = &item
+	}
+
+	if err := imp.loadExisting(ctx); err != nil {
+		return err
+	}
+
+	// Flows; new flows get their resource name from the cluster, use a placeholder meanwhile
+	imp.flowResourceNameMap = make(map[string]string)
+	for _, importFlow := range imp.data.TinyFlows {
+		if imp.existingFlowNames[importFlow.ResourceName] {
+			imp.flowResourceNameMap[importFlow.ResourceName] = importFlow.ResourceName
+			plan.Flows = append(plan.Flows, ImportPlanItem{Action: planActionSkip, Name: importFlow.ResourceName, Title: importFlow.Name, ID: importFlow.ResourceName, Reason: "flow already exists"})
+			continue
+		}
+		imp.flowResourceNameMap[importFlow.ResourceName] = plannedFlowName(importFlow.Name)
+		plan.Flows = append(plan.Flows, ImportPlanItem{Action: planActionCreate, Title: importFlow.Name, ID: importFlow.ResourceName})
+	}
+
+	// Nodes as they would look after the node step, keyed by cluster name
+	plannedNodes := make(map[string]*v1alpha1.TinyNode)
+	imp.nodeIDMap = make(map[string]string)
+	for _, elem := range imp.data.Elements {
+		elemType, _ := elem["type"].(string)
+		if elemType == "edge" || elemType == "tinyEdge" || elemType == "" {
+			continue
+		}
+		oldNodeID, _ := elem["id"].(string)
+		if oldNodeID == "" {
+			continue
+		}
+
+		if existing, exists := imp.existingNodesMap[oldNodeID]; exists {
+			imp.nodeIDMap[oldNodeID] = oldNodeID
+			updated := existing.DeepCopy()
+			item := ImportPlanItem{Action: planActionUnchanged, Name: oldNodeID, Title: existing.Annotations[v1alpha1.NodeLabelAnnotation], ID: oldNodeID}
+			if imp.edgeOnlyNodes[oldNodeID] {
+				item.Reason = "node of another flow, only its edges are imported"
+			} else if imp.applyImportedNode(updated, elem) {
+				if item.Changes = diffNodeFields(&existing, updated); len(item.Changes) > 0 {
+					item.Action = planActionUpdate
+				}
+			}
+			plannedNodes[oldNodeID] = updated
+			plan.Nodes = append(plan.Nodes, item)
+			continue
+		}
+
+		node, skipReason := imp.buildNode(oldNodeID, elem)
+		if node == nil {
+			plan.Nodes = append(plan.Nodes, ImportPlanItem{Action: planActionSkip, ID: oldNodeID, Reason: skipReason})
+			continue
+		}
+		name := node.Name
+		if isPlannedFlowName(node.Labels[v1alpha1.FlowNameLabel]) {
+			// The final name depends on the flow's generated resource name
+			name = ""
+		}
+		imp.nodeIDMap[oldNodeID] = node.Name
+		plannedNodes[node.Name] = node
+		plan.Nodes = append(plan.Nodes, ImportPlanItem{Action: planActionCreate, Name: name, Title: node.Annotations[v1alpha1.NodeLabelAnnotation], ID: oldNodeID})
+		for _, pc := range node.Spec.Ports {
+			plan.PortConfigs = append(plan.PortConfigs, ImportPlanItem{Action: planActionCreate, Name: portConfigKey(pc), Parent: name, ID: oldNodeID})
+		}
+	}
+
+	// Edges and edge configurations, compared per node against the planned node state
+	imp.collectEdges()
+	importedFlowNames := imp.importedFlowNames()
+	nodeNames := make([]string, 0)
+	for nodeName := range imp.nodesToUpdate() {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+
+	for _, nodeName := range nodeNames {
+		before, ok := plannedNodes[nodeName]
+		if !ok {
+			// A node outside the import that only loses pruned edges and edge configurations
+			existing, exists := imp.existingNodesMap[nodeName]
+			if !exists {
+				continue
+			}
+			before = &existing
+		}
+		after := before.DeepCopy()
+		imp.applyImportedEdges(after, importedFlowNames)
+		plan.Edges = append(plan.Edges, diffNodeEdges(before, after)...)
+		plan.PortConfigs = append(plan.PortConfigs, diffEdgePortConfigs(before, after)...)
+	}
+
+	// Pages and widgets
+	if len(imp.data.Pages) > 0 {
+		existingPages, err := imp.mgr.GetProjectPageWidgets(ctx, imp.projectName)
+		if err != nil {
+			return fmt.Errorf("unable to get existing pages: %w", err)
+		}
+		existingPageTitles := make(map[string]bool)
+		for _, page := range existingPages {
+			title := page.Annotations[v1alpha1.PageTitleAnnotation]
+			if title == "" {
+				title = page.Name
+			}
+			existingPageTitles[title] = true
+		}
+
+		for _, importPage := range imp.data.Pages {
+			if existingPageTitles[importPage.Title] {
+				plan.Pages = append(plan.Pages, ImportPlanItem{Action: planActionSkip, Title: importPage.Title, ID: importPage.Name, Reason: "page with this title already exists"})
+				for _, w := range importPage.Widgets {
+					plan.Widgets = append(plan.Widgets, ImportPlanItem{Action: planActionSkip, Title: w.Name, ID: w.Port, Parent: importPage.Title, Reason: "page is skipped"})
+				}
+				continue
+			}
+			plan.Pages = append(plan.Pages, ImportPlanItem{Action: planActionCreate, Title: importPage.Title, ID: importPage.Name})
+
+			widgets, skipped := imp.translateWidgets(importPage)
+			for _, w := range widgets {
+				plan.Widgets = append(plan.Widgets, ImportPlanItem{Action: planActionCreate, Name: w.Port, Title: w.Name, Parent: importPage.Title})
+			}
+			for port, reason := range skipped {
+				plan.Widgets = append(plan.Widgets, ImportPlanItem{Action: planActionSkip, ID: port, Parent: importPage.Title, Reason: reason})
+			}
+		}
+	}
+
+	// Scenarios
+	if len(imp.data.Scenarios) > 0 {
+		existingScenarioNames := make(map[string]bool)
+		if existing, err := imp.mgr.GetProjectScenarios(ctx, imp.projectName); err == nil {
+			for _, s := range existing {
+				name := s.Annotations[v1alpha1.ScenarioNameAnnotation]
+				if name == "" {
+					name = s.Name
+				}
+				existingScenarioNames[name] = true
+			}
+		}
+		for _, importScenario := range imp.data.Scenarios {
+			if existingScenarioNames[importScenario.Name] {
+				plan.Scenarios = append(plan.Scenarios, ImportPlanItem{Action: planActionSkip, Title: importScenario.Name, Reason: "scenario already exists"})
+				continue
+			}
+			plan.Scenarios = append(plan.Scenarios, ImportPlanItem{Action: planActionCreate, Title: importScenario.Name})
+		}
+	}
+
+	return nil
+}
+
+const plannedFlowPrefix = "new-flow:"
+
+// plannedFlowName stands in for the resource name of a flow the import would create.
+func plannedFlowName(title string) string {
+	return plannedFlowPrefix + title
+}
+
+func isPlannedFlowName(name string) bool {
+	return strings.HasPrefix(name, plannedFlowPrefix)
+}
+
+// portConfigKey identifies a port config: the port for node settings, "from->port" for edge configurations.
+func portConfigKey(pc v1alpha1.TinyNodePortConfig) string {
+	if pc.From == "" {
+		return pc.Port
+	}
+	return pc.From + "->" + pc.Port
+}
+
+// diffNodeFields lists label, annotation and port config changes between two versions of a node.
+func diffNodeFields(before, after *v1alpha1.TinyNode) []ImportFieldChange {
+	var changes []ImportFieldChange
+	changes = append(changes, diffStringMaps("labels.", before.Labels, after.Labels)...)
+	changes = append(changes, diffStringMaps("annotations.", before.Annotations, after.Annotations)...)
+
+	beforePorts := make(map[string]v1alpha1.TinyNodePortConfig)
+	for _, pc := range before.Spec.Ports {
+		beforePorts[portConfigKey(pc)] = pc
+	}
+	afterPorts := make(map[string]v1alpha1.TinyNodePortConfig)
+	for _, pc := range after.Spec.Ports {
+		afterPorts[portConfigKey(pc)] = pc
+	}
+	for _, key := range unionKeys(beforePorts, afterPorts) {
+		b, inBefore := beforePorts[key]
+		c, inAfter := afterPorts[key]
+		field := "ports[" + key + "]"
+		switch {
+		case !inBefore:
+			changes = append(changes, ImportFieldChange{Field: field + ".configuration", After: string(c.Configuration)})
+		case !inAfter:
+			changes = append(changes, ImportFieldChange{Field: field + ".configuration", Before: string(b.Configuration)})
+		default:
+			if string(b.Configuration) != string(c.Configuration) {
+				changes = append(changes, ImportFieldChange{Field: field + ".configuration", Before: string(b.Configuration), After: string(c.Configuration)})
+			}
+			if string(b.Schema) != string(c.Schema) {
+				changes = append(changes, ImportFieldChange{Field: field + ".schema", Before: string(b.Schema), After: string(c.Schema)})
+			}
+		}
+	}
+	return changes
+}
+
+// diffNodeEdges lists edges added, removed or kept on a source node.
+func diffNodeEdges(before, after *v1alpha1.TinyNode) []ImportPlanItem {
+	beforeEdges := make(map[string]v1alpha1.TinyNodeEdge)
+	for _, e := range before.Spec.Edges {
+		beforeEdges[e.ID] = e
+	}
+	afterEdges := make(map[string]v1alpha1.TinyNodeEdge)
+	for _, e := range after.Spec.Edges {
+		afterEdges[e.ID] = e
+	}
+
+	var items []ImportPlanItem
+	for _, id := range unionKeys(beforeEdges, afterEdges) {
+		b, inBefore := beforeEdges[id]
+		c, inAfter := afterEdges[id]
+		item := ImportPlanItem{Name: id, Parent: before.Name}
+		switch {
+		case !inBefore:
+			item.Action = planActionCreate
+			item.Title = c.Port + " -> " + c.To
+		case !inAfter:
+			item.Action = planActionDelete
+			item.Title = b.Port + " -> " + b.To
+			item.Reason = "stale edge of an imported flow"
+		case b == c:
+			item.Action = planActionUnchanged
+			item.Title = c.Port + " -> " + c.To
+		default:
+			item.Action = planActionUpdate
+			item.Title = c.Port + " -> " + c.To
+			item.Changes = []ImportFieldChange{{Field: "to", Before: b.To, After: c.To}, {Field: "flowID", Before: b.FlowID, After: c.FlowID}}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// diffEdgePortConfigs lists edge configurations added, removed or changed on a target node.
+func diffEdgePortConfigs(before, after *v1alpha1.TinyNode) []ImportPlanItem {
+	edgePorts := func(node *v1alpha1.TinyNode) map[string]v1alpha1.TinyNodePortConfig {
+		m := make(map[string]v1alpha1.TinyNodePortConfig)
+		for _, pc := range node.Spec.Ports {
+			if pc.From != "" {
+				m[portConfigKey(pc)] = pc
+			}
+		}
+		return m
+	}
+	beforePorts, afterPorts := edgePorts(before), edgePorts(after)
+
+	var items []ImportPlanItem
+	for _, key := range unionKeys(beforePorts, afterPorts) {
+		b, inBefore := beforePorts[key]
+		c, inAfter := afterPorts[key]
+		item := ImportPlanItem{Name: key, Parent: before.Name}
+		switch {
+		case !inBefore:
+			item.Action = planActionCreate
+		case !inAfter:
+			item.Action = planActionDelete
+			item.Reason = "stale edge configuration of an imported flow"
+		case string(b.Configuration) == string(c.Configuration) && string(b.Schema) == string(c.Schema):
+			item.Action = planActionUnchanged
+		default:
+			item.Action = planActionUpdate
+			if string(b.Configuration) != string(c.Configuration) {
+				item.Changes = append(item.Changes, ImportFieldChange{Field: "configuration", Before: string(b.Configuration), After: string(c.Configuration)})
+			}
+			if string(b.Schema) != string(c.Schema) {
+				item.Changes = append(item.Changes, ImportFieldChange{Field: "schema", Before: string(b.Schema), After: string(c.Schema)})
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// diffStringMaps lists keys whose values differ between two maps.
+func diffStringMaps(prefix string, before, after map[string]string) []ImportFieldChange {
+	var changes []ImportFieldChange
+	for _, key := range unionKeys(before, after) {
+		if before[key] != after[key] {
+			changes = append(changes, ImportFieldChange{Field: prefix + key, Before: before[key], After: after[key]})
+		}
+	}
+	return changes
+}
+
+// unionKeys returns the sorted keys present in either map.
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for _, m := range []map[string]V{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// summarize counts all plan items by action.
+func (p *ImportPlan) summarize() {
+	p.Summary = ImportPlanSummary{}
+	count := func(items []ImportPlanItem) {
+		for _, item := range items {
+			switch item.Action {
+			case planActionCreate:
+				p.Summary.Create++
+			case planActionUpdate:
+				p.Summary.Update++
+			case planActionDelete:
+				p.Summary.Delete++
+			case planActionSkip:
+				p.Summary.Skip++
+			case planActionUnchanged:
+				p.Summary.Unchanged++
+			}
+		}
+	}
+	if p.Project != nil {
+		count([]ImportPlanItem{*p.Project})
+	}
+	for _, items := range [][]ImportPlanItem{p.Flows, p.Nodes, p.Edges, p.PortConfigs, p.Pages, p.Widgets, p.Scenarios} {
+		count(items)
+	}
+}
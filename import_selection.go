@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tiny-systems/module/pkg/utils"
+)
+
+// ImportSelection picks parts of a project export to import. Flows are matched by
+// resource name or display name, pages by title or name, scenarios by name.
+type ImportSelection struct {
+	Flows     []string `json:"flows"`
+	Pages     []string `json:"pages"`
+	Scenarios []string `json:"scenarios"`
+}
+
+// selectOnly reduces the import data to the selection. Nodes from other flows that the selected
+// flows and pages depend on (nodes shared with them, edge endpoints or nodes shown by widgets)
+// are pulled in together with their flow entry, but without the rest of that flow; if they
+// already exist only their edges are imported. Pulled in nodes and dangling edge, widget and
+// scenario references are reported as warnings. The project description is not imported.
+func (imp *projectImporter) selectOnly(sel ImportSelection) error {
+	data := imp.data
+	if len(sel.Flows) == 0 && len(sel.Pages) == 0 && len(sel.Scenarios) == 0 {
+		return errors.New("nothing selected to import")
+	}
+
+	var notFound []string
+
+	// Flows
+	flowsByName := make(map[string]utils.ExportFlow)
+	for _, f := range data.TinyFlows {
+		flowsByName[f.ResourceName] = f
+		if _, ok := flowsByName[f.Name]; !ok {
+			flowsByName[f.Name] = f
+		}
+	}
+	selectedFlows := make(map[string]bool)
+	for _, name := range sel.Flows {
+		f, ok := flowsByName[name]
+		if !ok {
+			notFound = append(notFound, fmt.Sprintf("flow %q", name))
+			continue
+		}
+		selectedFlows[f.ResourceName] = true
+	}
+
+	// Pages
+	var pages []utils.ExportPage
+	for _, name := range sel.Pages {
+		found := false
+		for _, p := range data.Pages {
+			if p.Title == name || p.Name == name {
+				pages = append(pages, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			notFound = append(notFound, fmt.Sprintf("page %q", name))
+		}
+	}
+
+	// Scenarios
+	var scenarios []utils.ExportScenario
+	for _, name := range sel.Scenarios {
+		found := false
+		for _, s := range data.Scenarios {
+			if s.Name == name {
+				scenarios = append(scenarios, s)
+				found = true
+				break
+			}
+		}
+		if !found {
+			notFound = append(notFound, fmt.Sprintf("scenario %q", name))
+		}
+	}
+
+	if len(notFound) > 0 {
+		return fmt.Errorf("not found in import data: %s", strings.Join(notFound, ", "))
+	}
+
+	// Index node elements by id
+	nodeFlows := make(map[string]string)
+	for _, elem := range data.Elements {
+		if !isNodeElement(elem) {
+			continue
+		}
+		id, _ := elem["id"].(string)
+		flow, _ := elem["flow"].(string)
+		nodeFlows[id] = flow
+	}
+
+	keep := make(map[string]bool)
+	for id, flow := range nodeFlows {
+		if selectedFlows[flow] {
+			keep[id] = true
+		}
+	}
+
+	// Nodes of other flows shared with a selected flow
+	for _, elem := range data.Elements {
+		if !isNodeElement(elem) {
+			continue
+		}
+		id, _ := elem["id"].(string)
+		if keep[id] {
+			continue
+		}
+		elemData, _ := elem["data"].(map[string]interface{})
+		sharedWith, _ := elemData["shared_with_flows"].(string)
+		for flow := range selectedFlows {
+			if containsFlow(sharedWith, flow) {
+				keep[id] = true
+				imp.warnings = append(imp.warnings, fmt.Sprintf("pulled in node %s from flow %s: shared with selected flow %s", id, nodeFlows[id], flow))
+				break
+			}
+		}
+	}
+
+	// Nodes of other flows connected by edges of selected flows
+	var elements []map[string]interface{}
+	var edges []map[string]interface{}
+	for _, elem := range data.Elements {
+		if elemType, _ := elem["type"].(string); elemType != "edge" && elemType != "tinyEdge" {
+			continue
+		}
+		flow, _ := elem["flow"].(string)
+		if !selectedFlows[flow] {
+			continue
+		}
+		id, _ := elem["id"].(string)
+		source, _ := elem["source"].(string)
+		target, _ := elem["target"].(string)
+		if _, ok := nodeFlows[source]; !ok {
+			imp.warnings = append(imp.warnings, fmt.Sprintf("dangling edge %s: source node %s is not in the import data", id, source))
+			continue
+		}
+		if _, ok := nodeFlows[target]; !ok {
+			imp.warnings = append(imp.warnings, fmt.Sprintf("dangling edge %s: target node %s is not in the import data", id, target))
+			continue
+		}
+		for _, nodeID := range []string{source, target} {
+			if !keep[nodeID] {
+				keep[nodeID] = true
+				imp.warnings = append(imp.warnings, fmt.Sprintf("pulled in node %s from flow %s: connected by edge %s", nodeID, nodeFlows[nodeID], id))
+			}
+		}
+		edges = append(edges, elem)
+	}
+
+	// Nodes of other flows shown by widgets of selected pages
+	for _, p := range pages {
+		for _, w := range p.Widgets {
+			nodeID := portNodeID(w.Port)
+			if _, ok := nodeFlows[nodeID]; ok && !keep[nodeID] {
+				keep[nodeID] = true
+				imp.warnings = append(imp.warnings, fmt.Sprintf("pulled in node %s from flow %s: widget %q on page %q", nodeID, nodeFlows[nodeID], w.Name, p.Title))
+			}
+		}
+	}
+
+	// Flow entries of pulled in nodes are imported too, only for those nodes. Pulled in nodes
+	// that already exist keep their settings, so importing a flow doesn't roll back others.
+	imp.dependencyFlows = make(map[string]bool)
+	imp.edgeOnlyNodes = make(map[string]bool)
+	for id := range keep {
+		if flow := nodeFlows[id]; !selectedFlows[flow] {
+			imp.dependencyFlows[flow] = true
+			imp.edgeOnlyNodes[id] = true
+		}
+	}
+	var flows []utils.ExportFlow
+	for _, f := range data.TinyFlows {
+		if selectedFlows[f.ResourceName] || imp.dependencyFlows[f.ResourceName] {
+			flows = append(flows, f)
+		}
+	}
+
+	for _, elem := range data.Elements {
+		if !isNodeElement(elem) {
+			continue
+		}
+		if id, _ := elem["id"].(string); keep[id] {
+			elements = append(elements, elem)
+		}
+	}
+	elements = append(elements, edges...)
+
+	// References that will be skipped on import
+	for _, p := range pages {
+		for _, w := range p.Widgets {
+			if nodeID := portNodeID(w.Port); !keep[nodeID] {
+				imp.warnings = append(imp.warnings, fmt.Sprintf("dangling widget %q on page %q: node %s is not selected", w.Name, p.Title, nodeID))
+			}
+		}
+	}
+	for _, s := range scenarios {
+		for _, p := range s.Ports {
+			if nodeID := portNodeID(p.Port); !keep[nodeID] {
+				imp.warnings = append(imp.warnings, fmt.Sprintf("dangling port %s in scenario %q: node %s is not selected", p.Port, s.Name, nodeID))
+			}
+		}
+	}
+
+	imp.data = &utils.ProjectExport{
+		Version:   data.Version,
+		TinyFlows: flows,
+		Elements:  elements,
+		Pages:     pages,
+		Scenarios: scenarios,
+	}
+	return nil
+}
+
+// isNodeElement reports whether an export element is a node (not an edge).
+func isNodeElement(elem map[string]interface{}) bool {
+	elemType, _ := elem["type"].(string)
+	return elemType != "edge" && elemType != "tinyEdge" && elemType != ""
+}
+
+// portNodeID returns the node part of a "node:port" reference.
+func portNodeID(port string) string {
+	nodeID, _, _ := strings.Cut(port, ":")
+	return nodeID
+}
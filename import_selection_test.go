@@ -0,0 +1,159 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/tiny-systems/module/pkg/utils"
+)
+
+func TestSelectOnly(t *testing.T) {
+	data := func() *utils.ProjectExport {
+		var elements []map[string]interface{}
+		for _, v := range parseTestJSON(t, `[
+			{"id":"a1","type":"tinyNode","flow":"flow-a","data":{}},
+			{"id":"a2","type":"tinyNode","flow":"flow-a","data":{}},
+			{"id":"b1","type":"tinyNode","flow":"flow-b","data":{}},
+			{"id":"b2","type":"tinyNode","flow":"flow-b","data":{"shared_with_flows":"flow-x,flow-a"}},
+			{"id":"c1","type":"tinyNode","flow":"flow-c","data":{}},
+			{"id":"c2","type":"tinyNode","flow":"flow-c","data":{}},
+			{"id":"ea","type":"tinyEdge","flow":"flow-a","source":"a1","target":"b1"},
+			{"id":"ed","type":"tinyEdge","flow":"flow-a","source":"a2","target":"gone"},
+			{"id":"ec","type":"tinyEdge","flow":"flow-c","source":"c1","target":"c2"}
+		]`).([]interface{}) {
+			elements = append(elements, v.(map[string]interface{}))
+		}
+		return &utils.ProjectExport{
+			Version: utils.CurrentExportVersion,
+			TinyFlows: []utils.ExportFlow{
+				{ResourceName: "flow-a", Name: "Alpha"},
+				{ResourceName: "flow-b", Name: "Beta"},
+				{ResourceName: "flow-c", Name: "Gamma"},
+			},
+			Elements: elements,
+			Pages: []utils.ExportPage{
+				{Name: "page-1", Title: "Overview", Widgets: []utils.ExportWidget{
+					{Name: "Counter", Port: "c1:out"},
+					{Name: "Missing", Port: "gone:out"},
+				}},
+				{Name: "page-2", Title: "Details"},
+			},
+			Scenarios: []utils.ExportScenario{
+				{Name: "Smoke", Ports: []utils.ExportScenarioPortData{{Port: "a1:in"}, {Port: "c2:in"}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		sel           ImportSelection
+		wantErr       bool
+		wantFlows     []string
+		wantElements  []string
+		wantPages     []string
+		wantScenarios []string
+		wantEdgeOnly  []string
+		wantWarnings  []string
+	}{
+		{
+			name:    "nothing selected",
+			sel:     ImportSelection{},
+			wantErr: true,
+		},
+		{
+			name:    "unknown names",
+			sel:     ImportSelection{Flows: []string{"flow-a", "missing"}, Pages: []string{"missing"}},
+			wantErr: true,
+		},
+		{
+			name:         "flow by display name pulls in shared nodes and edge endpoints",
+			sel:          ImportSelection{Flows: []string{"Alpha"}},
+			wantFlows:    []string{"flow-a", "flow-b"},
+			wantElements: []string{"a1", "a2", "b1", "b2", "ea"},
+			wantEdgeOnly: []string{"b1", "b2"},
+			wantWarnings: []string{
+				"pulled in node b2 from flow flow-b: shared with selected flow flow-a",
+				"pulled in node b1 from flow flow-b: connected by edge ea",
+				"dangling edge ed: target node gone is not in the import data",
+			},
+		},
+		{
+			name:         "page pulls in its widget nodes",
+			sel:          ImportSelection{Pages: []string{"Overview"}},
+			wantFlows:    []string{"flow-c"},
+			wantElements: []string{"c1"},
+			wantPages:    []string{"page-1"},
+			wantEdgeOnly: []string{"c1"},
+			wantWarnings: []string{
+				"pulled in node c1 from flow flow-c: widget \"Counter\" on page \"Overview\"",
+				"dangling widget \"Missing\" on page \"Overview\": node gone is not selected",
+			},
+		},
+		{
+			name:          "flow with page and scenario by resource name",
+			sel:           ImportSelection{Flows: []string{"flow-c"}, Pages: []string{"page-2"}, Scenarios: []string{"Smoke"}},
+			wantFlows:     []string{"flow-c"},
+			wantElements:  []string{"c1", "c2", "ec"},
+			wantPages:     []string{"page-2"},
+			wantScenarios: []string{"Smoke"},
+			wantWarnings: []string{
+				"dangling port a1:in in scenario \"Smoke\": node a1 is not selected",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imp := &projectImporter{data: data()}
+			err := imp.selectOnly(tt.sel)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var flows, elements, pages, scenarios, edgeOnly []string
+			for _, f := range imp.data.TinyFlows {
+				flows = append(flows, f.ResourceName)
+			}
+			for _, elem := range imp.data.Elements {
+				id, _ := elem["id"].(string)
+				elements = append(elements, id)
+			}
+			for _, p := range imp.data.Pages {
+				pages = append(pages, p.Name)
+			}
+			for _, s := range imp.data.Scenarios {
+				scenarios = append(scenarios, s.Name)
+			}
+			for id := range imp.edgeOnlyNodes {
+				edgeOnly = append(edgeOnly, id)
+			}
+			sort.Strings(edgeOnly)
+
+			if !reflect.DeepEqual(flows, tt.wantFlows) {
+				t.Errorf("flows = %v, want %v", flows, tt.wantFlows)
+			}
+			if !reflect.DeepEqual(elements, tt.wantElements) {
+				t.Errorf("elements = %v, want %v", elements, tt.wantElements)
+			}
+			if !reflect.DeepEqual(pages, tt.wantPages) {
+				t.Errorf("pages = %v, want %v", pages, tt.wantPages)
+			}
+			if !reflect.DeepEqual(scenarios, tt.wantScenarios) {
+				t.Errorf("scenarios = %v, want %v", scenarios, tt.wantScenarios)
+			}
+			if !reflect.DeepEqual(edgeOnly, tt.wantEdgeOnly) {
+				t.Errorf("edge only nodes = %v, want %v", edgeOnly, tt.wantEdgeOnly)
+			}
+			if !reflect.DeepEqual(imp.warnings, tt.wantWarnings) {
+				t.Errorf("warnings =\n%q\nwant\n%q", imp.warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// importVariablePattern matches ${NAME} placeholders. Names are upper case so template
+// literals in code (${value}) are left alone.
+var importVariablePattern = regexp.MustCompile(`\$\{([A-Z_][A-Z0-9_]*)\}`)
+
+// ImportVariable is a ${NAME} placeholder used in handle or edge configurations of import data.
+type ImportVariable struct {
+	Name      string   `json:"name"`
+	Locations []string `json:"locations"` // nodes and edges using the variable
+}
+
+// ImportOptions narrows and parameterises an import.
+type ImportOptions struct {
+	Selection *ImportSelection  `json:"selection,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"` // values for ${NAME} placeholders
+	// SubstituteVariables replaces ${NAME} placeholders with Variables. Without it configurations
+	// are imported verbatim, so literals like ${HOME} survive clones and restores.
+	SubstituteVariables bool `json:"substituteVariables,omitempty"`
+
+	// pruneFlowEdges removes edges of the imported flows that aren't in the import
+	pruneFlowEdges bool
+}
+
+// ImportProjectWithOptions imports JSON data with an optional selection and values for placeholders.
+// With SubstituteVariables set, every placeholder in the imported configurations must have a value.
+func (a *App) ImportProjectWithOptions(contextName string, namespace string, projectName string, jsonData string, options ImportOptions) (*ImportResult, error) {
+	return a.importProject(contextName, namespace, projectName, jsonData, options)
+}
+
+// PlanImportWithOptions returns what ImportProjectWithOptions would do, without changing anything.
+func (a *App) PlanImportWithOptions(contextName string, namespace string, projectName string, jsonData string, options ImportOptions) (*ImportPlan, error) {
+	return a.planImport(contextName, namespace, projectName, jsonData, options)
+}
+
+// ListImportVariables returns the placeholders used in handle and edge configurations of import
+// data, only of the selected parts if options carry a selection.
+func (a *App) ListImportVariables(jsonData string, options ImportOptions) ([]ImportVariable, error) {
+	importData, err := a.parseProjectImport(jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &projectImporter{data: importData}
+	if options.Selection != nil {
+		if err := imp.selectOnly(*options.Selection); err != nil {
+			return nil, err
+		}
+	}
+
+	locations := make(map[string][]string)
+	imp.eachConfiguration(func(location string, value interface{}) interface{} {
+		walkStrings(value, func(s string) string {
+			for _, m := range importVariablePattern.FindAllStringSubmatch(s, -1) {
+				if l := locations[m[1]]; len(l) == 0 || l[len(l)-1] != location {
+					locations[m[1]] = append(l, location)
+				}
+			}
+			return s
+		})
+		return value
+	})
+
+	variables := make([]ImportVariable, 0, len(locations))
+	for name, l := range locations {
+		variables = append(variables, ImportVariable{Name: name, Locations: l})
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+	return variables, nil
+}
+
+// LoadImportVariablesFile asks for an env file and returns its KEY=VALUE pairs.
+func (a *App) LoadImportVariablesFile() (map[string]string, error) {
+	path, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Select env file",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Env Files", Pattern: "*.env"},
+			{DisplayName: "All Files", Pattern: "*"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		// User cancelled
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseEnvFile(f)
+}
+
+// LoadImportVariablesFromSecret returns the data of a Kubernetes Secret in the namespace as variables.
+func (a *App) LoadImportVariablesFromSecret(contextName string, namespace string, secretName string) (map[string]string, error) {
+	clientset, err := a.clients.clientset(contextName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client configuration: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get secret '%s': %w", secretName, err)
+	}
+
+	variables := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for k, v := range secret.Data {
+		variables[k] = string(v)
+	}
+	for k, v := range secret.StringData {
+		variables[k] = v
+	}
+	return variables, nil
+}
+
+// substituteVariables replaces placeholders in handle and edge configurations with values.
+// It returns the names of placeholders without a value, which are left as they are.
+func (imp *projectImporter) substituteVariables(values map[string]string) []string {
+	missing := make(map[string]bool)
+	imp.eachConfiguration(func(_ string, value interface{}) interface{} {
+		return walkStrings(value, func(s string) string {
+			return importVariablePattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+				name := importVariablePattern.FindStringSubmatch(placeholder)[1]
+				if v, ok := values[name]; ok {
+					return v
+				}
+				missing[name] = true
+				return placeholder
+			})
+		})
+	})
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// eachConfiguration calls fn with every handle and edge configuration of the import data
+// and stores what it returns.
+func (imp *projectImporter) eachConfiguration(fn func(location string, value interface{}) interface{}) {
+	for _, elem := range imp.data.Elements {
+		data, _ := elem["data"].(map[string]interface{})
+		if data == nil {
+			continue
+		}
+		id, _ := elem["id"].(string)
+
+		if !isNodeElement(elem) {
+			if config, ok := data["configuration"]; ok {
+				data["configuration"] = fn("edge "+id, config)
+			}
+			continue
+		}
+
+		handles, _ := data["handles"].([]interface{})
+		for _, h := range handles {
+			handle, ok := h.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if config, ok := handle["configuration"]; ok {
+				portID, _ := handle["id"].(string)
+				handle["configuration"] = fn("node "+id+" port "+portID, config)
+			}
+		}
+	}
+}
+
+// walkStrings returns value with fn applied to every string in it.
+func walkStrings(value interface{}, fn func(string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = walkStrings(child, fn)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = walkStrings(child, fn)
+		}
+		return v
+	case string:
+		return fn(v)
+	}
+	return value
+}
+
+// parseEnvFile reads KEY=VALUE lines, ignoring blank lines, comments and a leading "export".
+// Values may be single or double quoted.
+func parseEnvFile(r io.Reader) (map[string]string, error) {
+	variables := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		variables[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
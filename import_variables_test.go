@@ -0,0 +1,161 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tiny-systems/module/pkg/utils"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  map[string]string{},
+		},
+		{
+			name:  "comments, blank lines and export",
+			input: "# database\n\nDB_HOST=localhost\nexport DB_PORT=5432\n  # indented comment\n",
+			want:  map[string]string{"DB_HOST": "localhost", "DB_PORT": "5432"},
+		},
+		{
+			name:  "whitespace around keys and values",
+			input: "  NAME =  value with spaces  \n",
+			want:  map[string]string{"NAME": "value with spaces"},
+		},
+		{
+			name:  "double quotes unescape",
+			input: `GREETING="hello\nworld"` + "\n" + `EMPTY=""`,
+			want:  map[string]string{"GREETING": "hello\nworld", "EMPTY": ""},
+		},
+		{
+			name:  "single quotes are literal",
+			input: `PATTERN='a\nb "c"'`,
+			want:  map[string]string{"PATTERN": `a\nb "c"`},
+		},
+		{
+			name:  "equals signs in values",
+			input: "TOKEN=abc==\nURL=http://host/?a=b",
+			want:  map[string]string{"TOKEN": "abc==", "URL": "http://host/?a=b"},
+		},
+		{
+			name:  "later lines win",
+			input: "A=1\nA=2",
+			want:  map[string]string{"A": "2"},
+		},
+		{
+			name:    "line without equals sign",
+			input:   "A=1\nnot a variable",
+			wantErr: true,
+		},
+		{
+			name:    "broken double quotes",
+			input:   `A="unterminated\"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEnvFile(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEnvFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstituteVariables(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string // configuration of the node's handle
+		edgeConfig  string
+		values      map[string]string
+		wantConfig  string
+		wantEdge    string
+		wantMissing []string
+	}{
+		{
+			name:        "no placeholders",
+			config:      `{"url":"http://example.com","count":3}`,
+			edgeConfig:  `{"body":"plain"}`,
+			wantConfig:  `{"count":3,"url":"http://example.com"}`,
+			wantEdge:    `{"body":"plain"}`,
+			wantMissing: []string{},
+		},
+		{
+			name:        "nested values in nodes and edges",
+			config:      `{"db":{"dsn":"postgres://${DB_USER}:${DB_PASSWORD}@db"},"hosts":["${HOST}"]}`,
+			edgeConfig:  `{"auth":"Bearer ${TOKEN}"}`,
+			values:      map[string]string{"DB_USER": "app", "DB_PASSWORD": "secret", "HOST": "h1", "TOKEN": "t1"},
+			wantConfig:  `{"db":{"dsn":"postgres://app:secret@db"},"hosts":["h1"]}`,
+			wantEdge:    `{"auth":"Bearer t1"}`,
+			wantMissing: []string{},
+		},
+		{
+			name:        "missing values are kept and reported once",
+			config:      `{"a":"${B_VAR}","b":"${A_VAR} ${KNOWN}"}`,
+			edgeConfig:  `{"c":"${B_VAR}"}`,
+			values:      map[string]string{"KNOWN": "k"},
+			wantConfig:  `{"a":"${B_VAR}","b":"${A_VAR} k"}`,
+			wantEdge:    `{"c":"${B_VAR}"}`,
+			wantMissing: []string{"A_VAR", "B_VAR"},
+		},
+		{
+			name:        "lower case template literals are not placeholders",
+			config:      "{\"code\":\"`${value}`\"}",
+			edgeConfig:  `{"x":"$HOME and {HOME}"}`,
+			wantConfig:  "{\"code\":\"`${value}`\"}",
+			wantEdge:    `{"x":"$HOME and {HOME}"}`,
+			wantMissing: []string{},
+		},
+		{
+			name:        "empty value",
+			config:      `{"a":"[${EMPTY}]"}`,
+			edgeConfig:  `{}`,
+			values:      map[string]string{"EMPTY": ""},
+			wantConfig:  `{"a":"[]"}`,
+			wantEdge:    `{}`,
+			wantMissing: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handle := map[string]interface{}{"id": "settings", "configuration": parseTestJSON(t, tt.config)}
+			edgeData := map[string]interface{}{"configuration": parseTestJSON(t, tt.edgeConfig)}
+			imp := &projectImporter{data: &utils.ProjectExport{Elements: []map[string]interface{}{
+				{"id": "n1", "type": "tinyNode", "data": map[string]interface{}{"handles": []interface{}{handle}}},
+				{"id": "e1", "type": "tinyEdge", "source": "n0", "target": "n1", "data": edgeData},
+			}}}
+
+			missing := imp.substituteVariables(tt.values)
+
+			if got := testJSONString(t, handle["configuration"]); got != tt.wantConfig {
+				t.Errorf("configuration = %s, want %s", got, tt.wantConfig)
+			}
+			if got := testJSONString(t, edgeData["configuration"]); got != tt.wantEdge {
+				t.Errorf("edge configuration = %s, want %s", got, tt.wantEdge)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+}
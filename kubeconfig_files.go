@@ -0,0 +1,392 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Kubeconfig source kinds
+const (
+	kubeconfigSourceDefault  = "default"  // KUBECONFIG or ~/.kube/config
+	kubeconfigSourceAdded    = "added"    // a file or directory added in the app
+	kubeconfigSourceImported = "imported" // a pasted or opened kubeconfig stored by the app
+)
+
+// KubeconfigSource is a kubeconfig file or directory contexts are loaded from.
+type KubeconfigSource struct {
+	Path     string   `json:"path"`
+	Kind     string   `json:"kind"` // default, added or imported
+	IsDir    bool     `json:"isDir"`
+	Contexts []string `json:"contexts"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// kubeconfigSettings lists the kubeconfig files and directories added in the app.
+type kubeconfigSettings struct {
+	Paths []string `json:"paths"`
+}
+
+// kubeconfigFile is one file in the loading order, parsed.
+type kubeconfigFile struct {
+	path   string
+	kind   string
+	dir    string // added directory the file was found in
+	config *clientcmdapi.Config
+	err    error
+}
+
+var kubeconfigNameInvalid = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// GetKubeconfigSources lists where contexts are loaded from, in loading order. When a context
+// name is defined more than once, the first source wins.
+func (a *App) GetKubeconfigSources() ([]KubeconfigSource, error) {
+	settings, err := loadKubeconfigSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []KubeconfigSource
+	dirs := make(map[string]int)
+	for _, f := range kubeconfigFiles(settings) {
+		var source *KubeconfigSource
+		if f.dir != "" {
+			i, ok := dirs[f.dir]
+			if !ok {
+				i = len(sources)
+				dirs[f.dir] = i
+				sources = append(sources, KubeconfigSource{Path: f.dir, Kind: f.kind, IsDir: true})
+			}
+			source = &sources[i]
+		} else {
+			sources = append(sources, KubeconfigSource{Path: f.path, Kind: f.kind})
+			source = &sources[len(sources)-1]
+		}
+
+		if f.err != nil {
+			msg := fmt.Sprintf("%s: %v", filepath.Base(f.path), f.err)
+			if source.Error != "" {
+				msg = source.Error + "; " + msg
+			}
+			source.Error = msg
+			continue
+		}
+		if f.config == nil {
+			continue
+		}
+		for name := range f.config.Contexts {
+			source.Contexts = append(source.Contexts, name)
+		}
+		sort.Strings(source.Contexts)
+	}
+
+	// Added directories without files still show up, so they can be removed
+	for _, p := range settings.Paths {
+		if _, ok := dirs[p]; ok {
+			continue
+		}
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			sources = append(sources, KubeconfigSource{Path: p, Kind: kubeconfigSourceAdded, IsDir: true})
+		}
+	}
+	return sources, nil
+}
+
+// AddKubeconfigFile picks a kubeconfig file and adds it to the loaded files.
+// It returns the added path, "" if the dialog was cancelled.
+func (a *App) AddKubeconfigFile() (string, error) {
+	path, err := wailsruntime.OpenFileDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title: "Add kubeconfig file",
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	if _, err := clientcmd.LoadFromFile(path); err != nil {
+		return "", fmt.Errorf("not a valid kubeconfig: %w", err)
+	}
+	return path, a.addKubeconfigPath(path)
+}
+
+// AddKubeconfigDirectory picks a directory whose files are all loaded as kubeconfigs.
+// It returns the added path, "" if the dialog was cancelled.
+func (a *App) AddKubeconfigDirectory() (string, error) {
+	path, err := wailsruntime.OpenDirectoryDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title: "Add kubeconfig directory",
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	return path, a.addKubeconfigPath(path)
+}
+
+// ImportKubeconfig stores a kubeconfig, e.g. pasted or read with OpenFile, in the app's
+// config directory and loads it from then on. The name defaults to its current context.
+func (a *App) ImportKubeconfig(name, data string) (string, error) {
+	config, err := clientcmd.Load([]byte(data))
+	if err != nil {
+		return "", fmt.Errorf("not a valid kubeconfig: %w", err)
+	}
+	if len(config.Contexts) == 0 {
+		return "", fmt.Errorf("the kubeconfig has no contexts")
+	}
+
+	if name == "" {
+		name = config.CurrentContext
+	}
+	if name == "" {
+		for contextName := range config.Contexts {
+			name = contextName
+			break
+		}
+	}
+	name = strings.Trim(kubeconfigNameInvalid.ReplaceAllString(name, "-"), "-.")
+	if name == "" {
+		return "", fmt.Errorf("invalid kubeconfig name")
+	}
+
+	dir, err := importedKubeconfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name+".yaml")
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("a kubeconfig named %s is already imported", name)
+	}
+	// Kubeconfigs hold credentials
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		return "", fmt.Errorf("unable to save kubeconfig: %w", err)
+	}
+
+	a.kubeconfigChanged()
+	a.logger.Info("imported kubeconfig", "path", path, "contexts", len(config.Contexts))
+	return path, nil
+}
+
+// RemoveKubeconfigSource stops loading an added file or directory, or deletes an imported
+// kubeconfig. Default kubeconfig files can't be removed.
+func (a *App) RemoveKubeconfigSource(path string) error {
+	settings, err := loadKubeconfigSettings()
+	if err != nil {
+		return err
+	}
+	for i, p := range settings.Paths {
+		if p != path {
+			continue
+		}
+		settings.Paths = append(settings.Paths[:i], settings.Paths[i+1:]...)
+		if err := saveKubeconfigSettings(settings); err != nil {
+			return err
+		}
+		a.kubeconfigChanged()
+		return nil
+	}
+
+	dir, err := importedKubeconfigDir()
+	if err != nil {
+		return err
+	}
+	if filepath.Dir(path) == dir {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("unable to remove kubeconfig: %w", err)
+		}
+		a.kubeconfigChanged()
+		return nil
+	}
+	return fmt.Errorf("%s is not an added or imported kubeconfig", path)
+}
+
+// addKubeconfigPath saves a file or directory to the added paths.
+func (a *App) addKubeconfigPath(path string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	settings, err := loadKubeconfigSettings()
+	if err != nil {
+		return err
+	}
+	for _, p := range settings.Paths {
+		if p == path {
+			return nil
+		}
+	}
+	settings.Paths = append(settings.Paths, path)
+	if err := saveKubeconfigSettings(settings); err != nil {
+		return err
+	}
+	a.kubeconfigChanged()
+	a.logger.Info("added kubeconfig path", "path", path)
+	return nil
+}
+
+// kubeconfigChanged drops clients built from the previous set of kubeconfig files, as a
+// context name may now resolve to another file.
+func (a *App) kubeconfigChanged() {
+	a.clients.invalidate()
+	a.capabilities.invalidate()
+}
+
+// kubeconfigLoadingRules returns the default loading rules extended with the added and
+// imported kubeconfigs. Those that don't parse are left out, so one broken file doesn't
+// hide every context.
+func kubeconfigLoadingRules() *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	settings, err := loadKubeconfigSettings()
+	if err != nil {
+		return rules
+	}
+
+	var precedence []string
+	for _, f := range kubeconfigFiles(settings) {
+		if f.kind == kubeconfigSourceDefault || f.err == nil {
+			precedence = append(precedence, f.path)
+		}
+	}
+	rules.Precedence = precedence
+	return rules
+}
+
+// kubeconfigContextSources maps each context name to the file it is loaded from.
+func kubeconfigContextSources() map[string]string {
+	sources := make(map[string]string)
+	settings, err := loadKubeconfigSettings()
+	if err != nil {
+		return sources
+	}
+	for _, f := range kubeconfigFiles(settings) {
+		if f.config == nil {
+			continue
+		}
+		for name := range f.config.Contexts {
+			if _, ok := sources[name]; !ok {
+				sources[name] = f.path
+			}
+		}
+	}
+	return sources
+}
+
+// kubeconfigFiles returns the kubeconfig files in loading order: the default files, added
+// files and directories, then imported kubeconfigs. A path is only loaded once.
+func kubeconfigFiles(settings *kubeconfigSettings) []kubeconfigFile {
+	var files []kubeconfigFile
+	seen := make(map[string]bool)
+	add := func(path, kind, dir string) {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		f := kubeconfigFile{path: path, kind: kind, dir: dir}
+		f.config, f.err = clientcmd.LoadFromFile(path)
+		if os.IsNotExist(f.err) {
+			f.config, f.err = nil, nil
+		}
+		files = append(files, f)
+	}
+
+	for _, p := range clientcmd.NewDefaultClientConfigLoadingRules().GetLoadingPrecedence() {
+		add(p, kubeconfigSourceDefault, "")
+	}
+	for _, p := range settings.Paths {
+		info, err := os.Stat(p)
+		if err == nil && info.IsDir() {
+			for _, file := range kubeconfigDirFiles(p) {
+				add(file, kubeconfigSourceAdded, p)
+			}
+			continue
+		}
+		add(p, kubeconfigSourceAdded, "")
+	}
+	if dir, err := importedKubeconfigDir(); err == nil {
+		for _, file := range kubeconfigDirFiles(dir) {
+			add(file, kubeconfigSourceImported, "")
+		}
+	}
+	return files
+}
+
+// kubeconfigDirFiles returns the regular, non-hidden files of a directory, sorted.
+func kubeconfigDirFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !e.Type().IsRegular() {
+			continue
+		}
+		files = append(files, filepath.Join(dir, e.Name()))
+	}
+	return files
+}
+
+// kubeconfigSettingsPath returns the path of the added kubeconfig paths file.
+func kubeconfigSettingsPath() (string, error) {
+	usr, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(usr.HomeDir, ".config", "tinysystems", "kubeconfigs.json"), nil
+}
+
+// importedKubeconfigDir returns the directory imported kubeconfigs are stored in, creating it if needed.
+func importedKubeconfigDir() (string, error) {
+	usr, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(usr.HomeDir, ".config", "tinysystems", "kubeconfigs")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// loadKubeconfigSettings reads the added kubeconfig paths, empty if none were added yet.
+func loadKubeconfigSettings() (*kubeconfigSettings, error) {
+	path, err := kubeconfigSettingsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &kubeconfigSettings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var settings kubeconfigSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig settings %s: %w", path, err)
+	}
+	return &settings, nil
+}
+
+// saveKubeconfigSettings writes the added kubeconfig paths.
+func saveKubeconfigSettings(settings *kubeconfigSettings) error {
+	path, err := kubeconfigSettingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+`
+
+func TestKubeconfigFiles(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	def := write("default", testKubeconfig)
+	extra := write("extra", testKubeconfig)
+	broken := write("broken", "contexts: [")
+	dirB := write("dir/b", testKubeconfig)
+	dirA := write("dir/a", testKubeconfig)
+	write("dir/.hidden", testKubeconfig)
+	write("dir/sub/c", testKubeconfig)
+	missing := filepath.Join(root, "missing")
+	dir := filepath.Join(root, "dir")
+
+	type file struct {
+		path, kind, dir string
+		loaded, failed  bool
+	}
+	tests := []struct {
+		name     string
+		settings kubeconfigSettings
+		want     []file
+	}{
+		{
+			name: "default only",
+			want: []file{{path: def, kind: kubeconfigSourceDefault, loaded: true}},
+		},
+		{
+			name:     "added files after the default, each path once",
+			settings: kubeconfigSettings{Paths: []string{extra, def, extra}},
+			want: []file{
+				{path: def, kind: kubeconfigSourceDefault, loaded: true},
+				{path: extra, kind: kubeconfigSourceAdded, loaded: true},
+			},
+		},
+		{
+			name:     "directory expands to its visible files in order",
+			settings: kubeconfigSettings{Paths: []string{dir}},
+			want: []file{
+				{path: def, kind: kubeconfigSourceDefault, loaded: true},
+				{path: dirA, kind: kubeconfigSourceAdded, dir: dir, loaded: true},
+				{path: dirB, kind: kubeconfigSourceAdded, dir: dir, loaded: true},
+			},
+		},
+		{
+			name:     "missing files are listed without an error, broken ones with one",
+			settings: kubeconfigSettings{Paths: []string{missing, broken}},
+			want: []file{
+				{path: def, kind: kubeconfigSourceDefault, loaded: true},
+				{path: missing, kind: kubeconfigSourceAdded},
+				{path: broken, kind: kubeconfigSourceAdded, failed: true},
+			},
+		},
+	}
+
+	t.Setenv("KUBECONFIG", def)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []file
+			for _, f := range kubeconfigFiles(&tt.settings) {
+				// imported kubeconfigs live in the user's config directory
+				if f.kind == kubeconfigSourceImported || !strings.HasPrefix(f.path, root) {
+					continue
+				}
+				got = append(got, file{path: f.path, kind: f.kind, dir: f.dir, loaded: f.config != nil, failed: f.err != nil})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
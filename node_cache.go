@@ -0,0 +1,415 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// nodeCacheSyncTimeout bounds how long a caller waits for the initial list
+	nodeCacheSyncTimeout = 30 * time.Second
+	// nodeCacheWriteWait bounds how long a read waits for the cache to see the app's own writes
+	// before it lists the project from the API server instead
+	nodeCacheWriteWait = 2 * time.Second
+	// nodeCacheWriteExpiry drops expected writes the cache never reported, e.g. when the node
+	// was replaced by someone else in the meantime
+	nodeCacheWriteExpiry = 30 * time.Second
+	// watchFailedAttempts is how many consecutive list/watch errors mark a watch as failed
+	watchFailedAttempts = 5
+)
+
+// Watch connection states reported with watch:status events
+const (
+	watchStateConnected    = "connected"
+	watchStateReconnecting = "reconnecting"
+	watchStateFailed       = "failed"
+)
+
+// nodeCacheKey identifies the node cache of one project.
+type nodeCacheKey struct {
+	context   string
+	namespace string
+	project   string
+}
+
+// nodeWrite is a node write by the app that the cache is expected to catch up with.
+type nodeWrite struct {
+	resourceVersion string
+	deleted         bool
+	at              time.Time
+}
+
+// WatchStatus tells the frontend whether live updates of a project are flowing.
+type WatchStatus struct {
+	Context   string `json:"context"`
+	Namespace string `json:"namespace"`
+	Project   string `json:"project"`
+	Flow      string `json:"flow,omitempty"`
+	State     string `json:"state"` // connected, reconnecting or failed
+	Attempt   int    `json:"attempt"`
+	Error     string `json:"error,omitempty"`
+	// ResourceVersion is the last version seen; reconnects resume from it
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// projectNodeCache keeps an informer-backed copy of every TinyNode in a project,
+// so editor and dashboard calls don't list the whole project from the API server.
+type projectNodeCache struct {
+	key      nodeCacheKey
+	informer cache.SharedIndexInformer
+	ctx      context.Context
+	cancel   context.CancelFunc
+	onWatch  func(WatchStatus)
+
+	mu           sync.Mutex
+	pins         int
+	watchState   string
+	watchErr     error
+	watchAttempt int
+	lastUsed     time.Time
+	// writes holds node writes by the app the informer hasn't delivered yet, by node name
+	writes map[string]nodeWrite
+}
+
+func newProjectNodeCache(key nodeCacheKey, c client.WithWatch, onWatch func(WatchStatus)) *projectNodeCache {
+	selector := labels.SelectorFromSet(labels.Set{v1alpha1.ProjectNameLabel: key.project})
+
+	nc := &projectNodeCache{
+		key:        key,
+		onWatch:    onWatch,
+		lastUsed:   time.Now(),
+		watchState: watchStateReconnecting,
+		writes:     make(map[string]nodeWrite),
+	}
+
+	// The informer's reflector resumes watches from the last seen resourceVersion,
+	// relists when that version is gone (410) and backs off with jitter between attempts;
+	// the wrappers below only record the outcome of every list and watch call.
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			list := &v1alpha1.TinyNodeList{}
+			err := c.List(ctx, list, &client.ListOptions{
+				Namespace:     key.namespace,
+				LabelSelector: selector,
+				Limit:         opts.Limit,
+				Continue:      opts.Continue,
+				Raw:           &opts,
+			})
+			nc.setWatchResult(err)
+			return list, err
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			w, err := c.Watch(ctx, &v1alpha1.TinyNodeList{}, &client.ListOptions{
+				Namespace:     key.namespace,
+				LabelSelector: selector,
+				Raw:           &opts,
+			})
+			nc.setWatchResult(err)
+			return w, err
+		},
+	}
+
+	nc.informer = cache.NewSharedIndexInformer(lw, &v1alpha1.TinyNode{}, 0, cache.Indexers{})
+	_ = nc.informer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+		switch {
+		case errors.Is(err, io.EOF):
+			// watch closed normally, the reflector re-watches from the last resourceVersion
+		case apierrors.IsResourceExpired(err) || apierrors.IsGone(err):
+			// resourceVersion too old, the reflector relists before watching again
+			nc.setWatchResult(fmt.Errorf("resource version expired, relisting: %w", err))
+		case nc.isLastWatchErr(err):
+			// already recorded by the list/watch wrappers
+		default:
+			nc.setWatchResult(err)
+		}
+	})
+
+	return nc
+}
+
+// start runs the informer until stop is called.
+func (nc *projectNodeCache) start() {
+	nc.ctx, nc.cancel = context.WithCancel(context.Background())
+	go nc.informer.RunWithContext(nc.ctx)
+}
+
+// stop shuts the informer down.
+func (nc *projectNodeCache) stop() {
+	if nc.cancel != nil {
+		nc.cancel()
+	}
+}
+
+// waitForSync blocks until the initial list has been loaded or ctx expires.
+func (nc *projectNodeCache) waitForSync(ctx context.Context) error {
+	if nc.informer.HasSynced() {
+		return nil
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), nc.informer.HasSynced) {
+		return fmt.Errorf("timed out waiting for nodes of project %s", nc.key.project)
+	}
+	return nil
+}
+
+// done is closed once the cache has been stopped.
+func (nc *projectNodeCache) done() <-chan struct{} {
+	return nc.ctx.Done()
+}
+
+// pin keeps the cache from being evicted while a watch subscription reads from it.
+func (nc *projectNodeCache) pin() {
+	nc.mu.Lock()
+	nc.pins++
+	nc.mu.Unlock()
+}
+
+func (nc *projectNodeCache) unpin() {
+	nc.mu.Lock()
+	if nc.pins > 0 {
+		nc.pins--
+	}
+	nc.lastUsed = time.Now()
+	nc.mu.Unlock()
+}
+
+func (nc *projectNodeCache) pinned() bool {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	return nc.pins > 0
+}
+
+func (nc *projectNodeCache) touch() {
+	nc.mu.Lock()
+	nc.lastUsed = time.Now()
+	nc.mu.Unlock()
+}
+
+func (nc *projectNodeCache) idleSince() time.Time {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	return nc.lastUsed
+}
+
+// setWatchResult records the outcome of a list or watch call and reports state changes.
+func (nc *projectNodeCache) setWatchResult(err error) {
+	nc.mu.Lock()
+	prev := nc.watchState
+	if err == nil {
+		nc.watchState = watchStateConnected
+		nc.watchErr = nil
+		nc.watchAttempt = 0
+	} else {
+		nc.watchErr = err
+		nc.watchAttempt++
+		nc.watchState = watchStateReconnecting
+		if nc.watchAttempt >= watchFailedAttempts || apierrors.IsUnauthorized(err) || apierrors.IsForbidden(err) {
+			nc.watchState = watchStateFailed
+		}
+	}
+	changed := err != nil || prev != nc.watchState
+	nc.mu.Unlock()
+
+	if changed && nc.onWatch != nil {
+		nc.onWatch(nc.watchStatus())
+	}
+}
+
+func (nc *projectNodeCache) isLastWatchErr(err error) bool {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	return nc.watchErr != nil && errors.Is(err, nc.watchErr)
+}
+
+// watchStatus returns the connection state of the informer's watch.
+func (nc *projectNodeCache) watchStatus() WatchStatus {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	s := WatchStatus{
+		Context:         nc.key.context,
+		Namespace:       nc.key.namespace,
+		Project:         nc.key.project,
+		State:           nc.watchState,
+		Attempt:         nc.watchAttempt,
+		ResourceVersion: nc.informer.LastSyncResourceVersion(),
+	}
+	if nc.watchErr != nil {
+		s.Error = nc.watchErr.Error()
+	}
+	return s
+}
+
+// expectWrite records a node write by the app, see waitForWrites.
+func (nc *projectNodeCache) expectWrite(name string, w nodeWrite) {
+	nc.mu.Lock()
+	nc.writes[name] = w
+	nc.mu.Unlock()
+}
+
+// caughtUp drops the expected writes the informer has delivered and reports whether none
+// are left.
+func (nc *projectNodeCache) caughtUp() bool {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+
+	for name, w := range nc.writes {
+		if nc.seen(name, w) || time.Since(w.at) > nodeCacheWriteExpiry {
+			delete(nc.writes, name)
+		}
+	}
+	return len(nc.writes) == 0
+}
+
+// seen reports whether the cached copy of a node reflects a write: a deleted node is gone
+// or terminating, otherwise the cached resourceVersion is at least the written one.
+func (nc *projectNodeCache) seen(name string, w nodeWrite) bool {
+	obj, exists, err := nc.informer.GetIndexer().GetByKey(nc.key.namespace + "/" + name)
+	if err != nil {
+		return false
+	}
+	if w.deleted {
+		if !exists {
+			return true
+		}
+		node, ok := obj.(*v1alpha1.TinyNode)
+		return ok && node.DeletionTimestamp != nil
+	}
+	if !exists {
+		return false
+	}
+	node, ok := obj.(*v1alpha1.TinyNode)
+	return ok && resourceVersionAtLeast(node.ResourceVersion, w.resourceVersion)
+}
+
+// waitForWrites waits until the informer has delivered the app's own node writes. It
+// returns false if it didn't within nodeCacheWriteWait.
+func (nc *projectNodeCache) waitForWrites(ctx context.Context) bool {
+	if nc.caughtUp() {
+		return true
+	}
+	err := wait.PollUntilContextTimeout(ctx, 50*time.Millisecond, nodeCacheWriteWait, false, func(context.Context) (bool, error) {
+		return nc.caughtUp(), nil
+	})
+	return err == nil
+}
+
+// resourceVersionAtLeast compares resourceVersions. They are opaque to clients, but
+// Kubernetes uses increasing integers; anything else only matches exactly.
+func resourceVersionAtLeast(have, want string) bool {
+	h, herr := strconv.ParseUint(have, 10, 64)
+	w, werr := strconv.ParseUint(want, 10, 64)
+	if herr != nil || werr != nil {
+		return have == want
+	}
+	return h >= w
+}
+
+// nodes returns deep copies of all cached nodes sorted by name (same order as the API list),
+// so callers may modify and update them.
+func (nc *projectNodeCache) nodes() []v1alpha1.TinyNode {
+	objs := nc.informer.GetIndexer().List()
+	result := make([]v1alpha1.TinyNode, 0, len(objs))
+	for _, obj := range objs {
+		if node, ok := obj.(*v1alpha1.TinyNode); ok {
+			result = append(result, *node.DeepCopy())
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// nodesMap returns the cached nodes keyed by name. Values share slices and maps
+// with the cache and must be treated as read-only.
+func (nc *projectNodeCache) nodesMap() map[string]v1alpha1.TinyNode {
+	objs := nc.informer.GetIndexer().List()
+	result := make(map[string]v1alpha1.TinyNode, len(objs))
+	for _, obj := range objs {
+		if node, ok := obj.(*v1alpha1.TinyNode); ok {
+			result[node.Name] = *node
+		}
+	}
+	return result
+}
+
+// getNodeCache returns the synced node cache of a project, starting it if needed.
+func (a *App) getNodeCache(contextName, namespace, projectName string) (*projectNodeCache, error) {
+	ctx, cancel := context.WithTimeout(a.ctx, nodeCacheSyncTimeout)
+	defer cancel()
+
+	return a.clients.nodeCache(ctx, contextName, namespace, projectName)
+}
+
+// getProjectNodes returns all nodes of a project from the shared cache. If the cache hasn't
+// caught up with the app's own writes in time, they are listed from the API server.
+func (a *App) getProjectNodes(contextName, namespace, projectName string) ([]v1alpha1.TinyNode, error) {
+	nc, err := a.getNodeCache(contextName, namespace, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if !nc.waitForWrites(a.ctx) {
+		return a.listProjectNodes(contextName, namespace, projectName)
+	}
+	return nc.nodes(), nil
+}
+
+// getProjectNodesMap returns all nodes of a project keyed by name (read-only), from the
+// shared cache or, if it hasn't caught up with the app's own writes, from the API server.
+func (a *App) getProjectNodesMap(contextName, namespace, projectName string) (map[string]v1alpha1.TinyNode, error) {
+	nc, err := a.getNodeCache(contextName, namespace, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if !nc.waitForWrites(a.ctx) {
+		nodes, err := a.listProjectNodes(contextName, namespace, projectName)
+		if err != nil {
+			return nil, err
+		}
+		nodesMap := make(map[string]v1alpha1.TinyNode, len(nodes))
+		for _, node := range nodes {
+			nodesMap[node.Name] = node
+		}
+		return nodesMap, nil
+	}
+	return nc.nodesMap(), nil
+}
+
+// listProjectNodes lists the nodes of a project from the API server, bypassing the cache.
+func (a *App) listProjectNodes(contextName, namespace, projectName string) ([]v1alpha1.TinyNode, error) {
+	mgr, err := a.getManager(contextName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := mgr.GetProjectNodes(a.ctx, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project nodes: %w", err)
+	}
+	return nodes, nil
+}
+
+// emitWatchStatus forwards watch connection state to the frontend.
+func (a *App) emitWatchStatus(status WatchStatus) {
+	if a.ctx == nil {
+		return
+	}
+	wailsruntime.EventsEmit(a.ctx, "watch:status", status)
+}
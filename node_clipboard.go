@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"github.com/tiny-systems/module/pkg/utils"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// nodeClipboardKind marks clipboard text as copied nodes
+	nodeClipboardKind    = "tinysystems.io/nodes"
+	nodeClipboardVersion = 1
+)
+
+// NodeClipboard is a copied selection of nodes and the edges among them, in the element
+// format of project exports.
+type NodeClipboard struct {
+	Kind     string                   `json:"kind"`
+	Version  int                      `json:"version"`
+	Elements []map[string]interface{} `json:"elements"`
+}
+
+// CopyNodes returns the nodes as a clipboard payload for PasteNodes. Edges to nodes
+// outside the selection are left out.
+func (a *App) CopyNodes(contextName, namespace, projectName string, nodeNames []string) (string, error) {
+	if len(nodeNames) == 0 {
+		return "", fmt.Errorf("no nodes provided")
+	}
+
+	allNodesMap, err := a.getProjectNodesMap(contextName, namespace, projectName)
+	if err != nil {
+		return "", fmt.Errorf("failed to list project nodes: %w", err)
+	}
+
+	selected := make(map[string]v1alpha1.TinyNode, len(nodeNames))
+	for _, name := range nodeNames {
+		node, ok := allNodesMap[name]
+		if !ok {
+			return "", fmt.Errorf("node %s not found", name)
+		}
+		selected[name] = node
+	}
+
+	elements, err := exportElements(selected)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(NodeClipboard{
+		Kind:     nodeClipboardKind,
+		Version:  nodeClipboardVersion,
+		Elements: filterExportElements(elements, selected),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// PasteNodes creates the nodes of a CopyNodes payload in a flow, which may be in another
+// project or cluster. Nodes get new names and are moved by the offset; edges among them
+// are rewired to the new nodes. It returns which node each copied node became.
+func (a *App) PasteNodes(contextName, namespace, projectName, flowResourceName, payload string, offsetX, offsetY float64) ([]CloneMapping, error) {
+	if err := a.requireWritable(contextName, namespace, createNodes); err != nil {
+		return nil, err
+	}
+
+	var clipboard NodeClipboard
+	if err := json.Unmarshal([]byte(payload), &clipboard); err != nil || clipboard.Kind != nodeClipboardKind {
+		return nil, fmt.Errorf("clipboard doesn't contain copied nodes")
+	}
+	if clipboard.Version != nodeClipboardVersion {
+		return nil, fmt.Errorf("unsupported clipboard version: %d", clipboard.Version)
+	}
+
+	mgr, err := a.getManager(contextName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every copied flow maps to the target flow
+	flowMap := make(map[string]string)
+	for _, elem := range clipboard.Elements {
+		if flow, _ := elem["flow"].(string); flow != "" {
+			flowMap[flow] = flowResourceName
+		}
+		if !isNodeElement(elem) {
+			continue
+		}
+		if position, ok := elem["position"].(map[string]interface{}); ok {
+			x, _ := position["x"].(float64)
+			y, _ := position["y"].(float64)
+			position["x"] = x + offsetX
+			position["y"] = y + offsetY
+		}
+		// The copies belong to the target flow only
+		if data, ok := elem["data"].(map[string]interface{}); ok {
+			delete(data, "shared_with_flows")
+		}
+	}
+
+	imp := &projectImporter{
+		app:         a,
+		mgr:         mgr,
+		namespace:   namespace,
+		projectName: projectName,
+		data: &utils.ProjectExport{
+			Version:  utils.CurrentExportVersion,
+			Elements: clipboard.Elements,
+		},
+		journal: &importJournal{},
+		progress: func(msg string) {
+			a.logger.Info("paste nodes: " + msg)
+		},
+		limiter:             flowcontrol.NewTokenBucketRateLimiter(importQPS, importBurst),
+		flowResourceNameMap: flowMap,
+		freshNodeNames:      true,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	steps := []func(context.Context) error{
+		imp.importNodes,
+		imp.waitForNodes,
+		imp.importEdges,
+		imp.verifyPortConfigs,
+	}
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			rollbackCtx, rollbackCancel := context.WithTimeout(context.Background(), importRollbackTimeout)
+			defer rollbackCancel()
+			_, rollbackErrors := imp.journal.rollback(rollbackCtx, imp.progress)
+			for _, e := range rollbackErrors {
+				a.logger.Info("rollback error: " + e)
+			}
+			return nil, fmt.Errorf("paste failed: %w", err)
+		}
+	}
+
+	mapping := make([]CloneMapping, 0, len(imp.nodeIDMap))
+	for _, elem := range clipboard.Elements {
+		if !isNodeElement(elem) {
+			continue
+		}
+		id, _ := elem["id"].(string)
+		target, ok := imp.nodeIDMap[id]
+		if !ok {
+			continue
+		}
+		data, _ := elem["data"].(map[string]interface{})
+		label, _ := data["label"].(string)
+		mapping = append(mapping, CloneMapping{Source: id, Target: target, Title: label})
+	}
+	sort.Slice(mapping, func(i, j int) bool {
+		return mapping[i].Source < mapping[j].Source
+	})
+
+	for _, w := range imp.warnings {
+		a.logger.Info("paste nodes warning: " + w)
+	}
+	return mapping, nil
+}
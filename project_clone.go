@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/tiny-systems/module/api/v1alpha1"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// CloneMapping maps a source resource to the resource created for it in the destination.
+type CloneMapping struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Title  string `json:"title,omitempty"`
+}
+
+// CloneResult reports where a project was cloned to and how its resources were renamed.
+type CloneResult struct {
+	Project Project        `json:"project"`
+	Flows   []CloneMapping `json:"flows"`
+	Nodes   []CloneMapping `json:"nodes"`
+	Import  *ImportResult  `json:"import"`
+}
+
+// CloneProject copies a project into a new project, in another namespace or cluster or next to
+// the source. Progress is emitted as clone:progress events and CancelImport stops it. If copying
+// fails the destination project is removed again.
+func (a *App) CloneProject(srcContext string, srcNamespace string, srcProject string, dstContext string, dstNamespace string, dstProjectTitle string) (*CloneResult, error) {
+	if err := a.requireWritable(dstContext, dstNamespace, createProjects, createFlows, createNodes, createPages); err != nil {
+		return nil, err
+	}
+
+	if dstProjectTitle == "" {
+		return nil, fmt.Errorf("destination project title is required")
+	}
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), importTimeout)
+	defer timeoutCancel()
+
+	ctx, cancel := context.WithCancelCause(timeoutCtx)
+	defer cancel(nil)
+
+	if err := a.beginImport(cancel); err != nil {
+		return nil, err
+	}
+	defer a.endImport()
+
+	emitProgress := func(msg string) {
+		wailsruntime.EventsEmit(a.ctx, "clone:progress", msg)
+	}
+
+	emitProgress("Exporting source project...")
+
+	export, err := a.buildProjectExport(srcContext, srcNamespace, srcProject, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to export source project: %w", err)
+	}
+
+	// The importer works on decoded JSON, as if the export had been saved and opened
+	data, err := json.Marshal(export)
+	if err != nil {
+		return nil, err
+	}
+	importData, err := a.parseProjectImport(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	emitProgress("Creating destination project...")
+
+	mgr, err := a.getManager(dstContext, dstNamespace)
+	if err != nil {
+		return nil, err
+	}
+	project, err := mgr.CreateProject(ctx, dstNamespace, dstProjectTitle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+	a.logger.Info("cloning project", "source", srcProject, "srcContext", srcContext, "srcNamespace", srcNamespace,
+		"target", project.Name, "dstContext", dstContext, "dstNamespace", dstNamespace)
+
+	imp, importResult, err := a.applyImport(ctx, dstContext, dstNamespace, project.Name, importData, ImportOptions{}, emitProgress)
+	if err != nil {
+		// The import rolled back its own changes, the project is all that is left
+		deleteCtx, deleteCancel := context.WithTimeout(context.Background(), importRollbackTimeout)
+		defer deleteCancel()
+		if deleteErr := mgr.DeleteProject(deleteCtx, project.Name); deleteErr != nil {
+			a.logger.Error(deleteErr, "failed to delete project of failed clone", "project", project.Name)
+			return nil, fmt.Errorf("%w\nproject %s could not be removed: %v", err, project.Name, deleteErr)
+		}
+		return nil, err
+	}
+
+	title := project.Annotations[v1alpha1.ProjectNameAnnotation]
+	if title == "" {
+		title = project.Name
+	}
+
+	result := &CloneResult{
+		Project: Project{
+			Name:        project.Name,
+			Title:       title,
+			Description: importData.Description,
+		},
+		Flows:  []CloneMapping{},
+		Nodes:  []CloneMapping{},
+		Import: importResult,
+	}
+
+	for _, flow := range importData.TinyFlows {
+		if target, ok := imp.flowResourceNameMap[flow.ResourceName]; ok {
+			result.Flows = append(result.Flows, CloneMapping{Source: flow.ResourceName, Target: target, Title: flow.Name})
+		}
+	}
+
+	for _, elem := range importData.Elements {
+		if !isNodeElement(elem) {
+			continue
+		}
+		id, _ := elem["id"].(string)
+		target, ok := imp.nodeIDMap[id]
+		if !ok {
+			continue
+		}
+		data, _ := elem["data"].(map[string]interface{})
+		label, _ := data["label"].(string)
+		result.Nodes = append(result.Nodes, CloneMapping{Source: id, Target: target, Title: label})
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool {
+		return result.Nodes[i].Source < result.Nodes[j].Source
+	})
+
+	emitProgress(fmt.Sprintf("Cloned %s to %s (%d flows, %d nodes)", srcProject, project.Name, len(result.Flows), len(result.Nodes)))
+	return result, nil
+}
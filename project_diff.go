@@ -0,0 +1,535 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tiny-systems/module/pkg/utils"
+)
+
+// Diff actions
+const (
+	diffActionAdded   = "added"
+	diffActionRemoved = "removed"
+	diffActionChanged = "changed"
+)
+
+// DiffSource is one side of a project or flow diff: a project in a cluster, or the content of
+// an export (JSON, YAML or a manifest bundle) when Data is set.
+type DiffSource struct {
+	Context   string `json:"context,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Project   string `json:"project,omitempty"`
+	Data      string `json:"data,omitempty"`
+	Flow      string `json:"flow,omitempty"` // flow resource name, or flow ID in the export, for DiffFlows
+}
+
+// DiffItem is a resource that was added, removed or changed between two sources.
+type DiffItem struct {
+	Action  string              `json:"action"` // added, removed or changed
+	Key     string              `json:"key"`    // what the two sides are matched by
+	From    string              `json:"from,omitempty"`
+	To      string              `json:"to,omitempty"`
+	Title   string              `json:"title,omitempty"`
+	Changes []ImportFieldChange `json:"changes,omitempty"`
+}
+
+// DiffSummary counts diff items by action.
+type DiffSummary struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+// ProjectDiff is what differs between two projects or flows. Node, widget and scenario port
+// references are compared by node key, so generated node names don't show up as changes.
+type ProjectDiff struct {
+	Flows     []DiffItem  `json:"flows"`
+	Nodes     []DiffItem  `json:"nodes"`
+	Edges     []DiffItem  `json:"edges"`
+	Pages     []DiffItem  `json:"pages"`
+	Widgets   []DiffItem  `json:"widgets"`
+	Scenarios []DiffItem  `json:"scenarios"`
+	Summary   DiffSummary `json:"summary"`
+}
+
+// DiffProjects compares two projects: two clusters, a cluster and an export, or two exports.
+// Flows are matched by title and nodes by flow, component and label, so a project compares
+// equal to an import of its own export.
+func (a *App) DiffProjects(from DiffSource, to DiffSource) (*ProjectDiff, error) {
+	before, err := a.loadDiffSource(from, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load first project: %w", err)
+	}
+	after, err := a.loadDiffSource(to, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load second project: %w", err)
+	}
+	return diffExports(before, after, false), nil
+}
+
+// DiffFlows compares the nodes and edges of one flow of each source. The flows don't need to
+// have the same title, so a flow can be compared with a copy of it.
+func (a *App) DiffFlows(from DiffSource, to DiffSource) (*ProjectDiff, error) {
+	if from.Flow == "" || to.Flow == "" {
+		return nil, fmt.Errorf("a flow is required on both sides")
+	}
+	before, err := a.loadDiffSource(from, true)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load first flow: %w", err)
+	}
+	after, err := a.loadDiffSource(to, true)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load second flow: %w", err)
+	}
+	return diffExports(before, after, true), nil
+}
+
+// loadDiffSource exports a cluster project or decodes export data. With flowOnly only the
+// elements of the source's flow are kept.
+func (a *App) loadDiffSource(src DiffSource, flowOnly bool) (*utils.ProjectExport, error) {
+	var export *utils.ProjectExport
+	if src.Data != "" {
+		decoded, err := decodeProjectImport(src.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid export data: %w", err)
+		}
+		utils.StripSchemaInternalFields(decoded)
+		export = decoded
+	} else {
+		if src.Context == "" || src.Namespace == "" || src.Project == "" {
+			return nil, fmt.Errorf("context, namespace and project are required")
+		}
+		var selection *ExportSelection
+		if flowOnly {
+			selection = &ExportSelection{Flows: []string{src.Flow}}
+		}
+		built, err := a.buildProjectExport(src.Context, src.Namespace, src.Project, selection)
+		if err != nil {
+			return nil, err
+		}
+		if export, err = decodedExport(built); err != nil {
+			return nil, err
+		}
+	}
+
+	if !flowOnly {
+		return export, nil
+	}
+
+	var flows []utils.ExportFlow
+	for _, f := range export.TinyFlows {
+		if f.ResourceName == src.Flow {
+			flows = append(flows, f)
+		}
+	}
+	if len(flows) == 0 {
+		return nil, fmt.Errorf("flow %s not found", src.Flow)
+	}
+	// Nodes of other flows that a selection export brings along aren't part of the flow
+	var elements []map[string]interface{}
+	for _, elem := range export.Elements {
+		if flow, _ := elem["flow"].(string); flow == src.Flow {
+			elements = append(elements, elem)
+		}
+	}
+	return &utils.ProjectExport{
+		Version:   export.Version,
+		TinyFlows: flows,
+		Elements:  elements,
+	}, nil
+}
+
+// decodedExport round-trips an export through JSON, so its elements hold the same types as
+// an export read from a file.
+func decodedExport(export *utils.ProjectExport) (*utils.ProjectExport, error) {
+	data, err := json.Marshal(export)
+	if err != nil {
+		return nil, err
+	}
+	var decoded utils.ProjectExport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// diffIndex is one side of a diff, keyed by what is compared across sources.
+type diffIndex struct {
+	flowTitles map[string]string // flow resource name -> title
+	nodeKeys   map[string]string // node ID -> node key
+	nodes      map[string]map[string]interface{}
+	edges      map[string]map[string]interface{}
+}
+
+// diffExports compares two exports. With singleFlow the flow titles are left out of the
+// node keys, as each side holds one flow.
+func diffExports(before, after *utils.ProjectExport, singleFlow bool) *ProjectDiff {
+	b := indexDiffExport(before, singleFlow)
+	c := indexDiffExport(after, singleFlow)
+
+	diff := &ProjectDiff{}
+	if !singleFlow {
+		diff.Flows = diffFlowTitles(b.flowTitles, c.flowTitles)
+	}
+
+	for _, key := range unionKeys(b.nodes, c.nodes) {
+		bNode, inBefore := b.nodes[key]
+		cNode, inAfter := c.nodes[key]
+		item := DiffItem{Key: key}
+		if inBefore {
+			item.From, _ = bNode["id"].(string)
+			item.Title = elementLabel(bNode)
+		}
+		if inAfter {
+			item.To, _ = cNode["id"].(string)
+			item.Title = elementLabel(cNode)
+		}
+		switch {
+		case !inBefore:
+			item.Action = diffActionAdded
+		case !inAfter:
+			item.Action = diffActionRemoved
+		default:
+			item.Changes = diffNodeElements(normalizeDiffNode(bNode, b.flowTitles), normalizeDiffNode(cNode, c.flowTitles))
+			if len(item.Changes) == 0 {
+				continue
+			}
+			item.Action = diffActionChanged
+		}
+		diff.Nodes = append(diff.Nodes, item)
+	}
+
+	for _, key := range unionKeys(b.edges, c.edges) {
+		bEdge, inBefore := b.edges[key]
+		cEdge, inAfter := c.edges[key]
+		item := DiffItem{Key: key}
+		if inBefore {
+			item.From, _ = bEdge["id"].(string)
+		}
+		if inAfter {
+			item.To, _ = cEdge["id"].(string)
+		}
+		switch {
+		case !inBefore:
+			item.Action = diffActionAdded
+		case !inAfter:
+			item.Action = diffActionRemoved
+		default:
+			bData, _ := bEdge["data"].(map[string]interface{})
+			cData, _ := cEdge["data"].(map[string]interface{})
+			change, ok := diffJSONField("configuration", bData["configuration"], cData["configuration"])
+			if !ok {
+				continue
+			}
+			item.Action = diffActionChanged
+			item.Changes = []ImportFieldChange{change}
+		}
+		diff.Edges = append(diff.Edges, item)
+	}
+
+	if !singleFlow {
+		diff.Pages, diff.Widgets = diffPages(before.Pages, after.Pages, b.nodeKeys, c.nodeKeys)
+		diff.Scenarios = diffScenarios(before.Scenarios, after.Scenarios, b.nodeKeys, c.nodeKeys)
+	}
+
+	diff.summarize()
+	return diff
+}
+
+// indexDiffExport keys nodes by "flow/component/label" and edges by the node keys and ports
+// they connect. Nodes sharing a key are told apart by their position on the canvas.
+func indexDiffExport(export *utils.ProjectExport, singleFlow bool) *diffIndex {
+	idx := &diffIndex{
+		flowTitles: make(map[string]string),
+		nodeKeys:   make(map[string]string),
+		nodes:      make(map[string]map[string]interface{}),
+		edges:      make(map[string]map[string]interface{}),
+	}
+	for _, f := range export.TinyFlows {
+		title := f.Name
+		if title == "" {
+			title = f.ResourceName
+		}
+		idx.flowTitles[f.ResourceName] = title
+	}
+
+	byKey := make(map[string][]map[string]interface{})
+	for _, elem := range export.Elements {
+		if !isNodeElement(elem) {
+			continue
+		}
+		var flow string
+		if !singleFlow {
+			flow, _ = elem["flow"].(string)
+			if title, ok := idx.flowTitles[flow]; ok {
+				flow = title
+			}
+		}
+		data, _ := elem["data"].(map[string]interface{})
+		component, _ := data["component"].(string)
+		key := flow + "/" + component + "/" + elementLabel(elem)
+		byKey[key] = append(byKey[key], elem)
+	}
+	for key, elems := range byKey {
+		sort.SliceStable(elems, func(i, j int) bool {
+			xi, yi := elementPosition(elems[i])
+			xj, yj := elementPosition(elems[j])
+			if yi != yj {
+				return yi < yj
+			}
+			if xi != xj {
+				return xi < xj
+			}
+			idi, _ := elems[i]["id"].(string)
+			idj, _ := elems[j]["id"].(string)
+			return idi < idj
+		})
+		for i, elem := range elems {
+			nodeKey := key
+			if i > 0 {
+				nodeKey += "#" + strconv.Itoa(i+1)
+			}
+			id, _ := elem["id"].(string)
+			idx.nodeKeys[id] = nodeKey
+			idx.nodes[nodeKey] = elem
+		}
+	}
+
+	for _, elem := range export.Elements {
+		if isNodeElement(elem) {
+			continue
+		}
+		source, _ := elem["source"].(string)
+		sourceHandle, _ := elem["sourceHandle"].(string)
+		target, _ := elem["target"].(string)
+		targetHandle, _ := elem["targetHandle"].(string)
+		idx.edges[idx.nodeKey(source)+":"+sourceHandle+" -> "+idx.nodeKey(target)+":"+targetHandle] = elem
+	}
+	return idx
+}
+
+// nodeKey returns the key of a node ID, or the ID itself for nodes outside the export.
+func (idx *diffIndex) nodeKey(id string) string {
+	if key, ok := idx.nodeKeys[id]; ok {
+		return key
+	}
+	return id
+}
+
+// portKey translates a "nodeID:port" reference to "nodeKey:port".
+func (idx *diffIndex) portKey(port string) string {
+	parts := strings.SplitN(port, ":", 2)
+	if len(parts) != 2 {
+		return port
+	}
+	return idx.nodeKey(parts[0]) + ":" + parts[1]
+}
+
+// elementPosition returns the canvas position of a node element.
+func elementPosition(elem map[string]interface{}) (x, y float64) {
+	pos, _ := elem["position"].(map[string]interface{})
+	x, _ = pos["x"].(float64)
+	y, _ = pos["y"].(float64)
+	return x, y
+}
+
+// normalizeDiffNode returns a copy of a node element with the flows it is shared with
+// given by title, as resource names differ between sources.
+func normalizeDiffNode(elem map[string]interface{}, flowTitles map[string]string) map[string]interface{} {
+	data, _ := elem["data"].(map[string]interface{})
+	sharedWith, _ := data["shared_with_flows"].(string)
+	if sharedWith == "" {
+		return elem
+	}
+	var titles []string
+	for _, flow := range strings.Split(sharedWith, ",") {
+		flow = strings.TrimSpace(flow)
+		if title, ok := flowTitles[flow]; ok {
+			flow = title
+		}
+		titles = append(titles, flow)
+	}
+	sort.Strings(titles)
+
+	normalizedData := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		normalizedData[k] = v
+	}
+	normalizedData["shared_with_flows"] = strings.Join(titles, ",")
+	normalized := make(map[string]interface{}, len(elem))
+	for k, v := range elem {
+		normalized[k] = v
+	}
+	normalized["data"] = normalizedData
+	return normalized
+}
+
+// diffFlowTitles lists flows that exist on only one side, matched by title.
+func diffFlowTitles(before, after map[string]string) []DiffItem {
+	byTitle := func(titles map[string]string) map[string]string {
+		m := make(map[string]string, len(titles))
+		for name, title := range titles {
+			m[title] = name
+		}
+		return m
+	}
+	b, c := byTitle(before), byTitle(after)
+
+	var items []DiffItem
+	for _, title := range unionKeys(b, c) {
+		from, inBefore := b[title]
+		to, inAfter := c[title]
+		switch {
+		case !inBefore:
+			items = append(items, DiffItem{Action: diffActionAdded, Key: title, To: to, Title: title})
+		case !inAfter:
+			items = append(items, DiffItem{Action: diffActionRemoved, Key: title, From: from, Title: title})
+		}
+	}
+	return items
+}
+
+// diffPages compares dashboard pages by title and their widgets by the port they show.
+func diffPages(before, after []utils.ExportPage, beforeKeys, afterKeys map[string]string) (pages, widgets []DiffItem) {
+	bIdx := &diffIndex{nodeKeys: beforeKeys}
+	cIdx := &diffIndex{nodeKeys: afterKeys}
+
+	indexPages := func(list []utils.ExportPage) map[string]utils.ExportPage {
+		m := make(map[string]utils.ExportPage, len(list))
+		for _, p := range list {
+			m[p.Title] = p
+		}
+		return m
+	}
+	indexWidgets := func(page utils.ExportPage, idx *diffIndex) map[string]utils.ExportWidget {
+		m := make(map[string]utils.ExportWidget, len(page.Widgets))
+		for _, w := range page.Widgets {
+			m[page.Title+"/"+idx.portKey(w.Port)] = w
+		}
+		return m
+	}
+
+	bPages, cPages := indexPages(before), indexPages(after)
+	for _, title := range unionKeys(bPages, cPages) {
+		bPage, inBefore := bPages[title]
+		cPage, inAfter := cPages[title]
+		switch {
+		case !inBefore:
+			pages = append(pages, DiffItem{Action: diffActionAdded, Key: title, To: cPage.Name, Title: title})
+		case !inAfter:
+			pages = append(pages, DiffItem{Action: diffActionRemoved, Key: title, From: bPage.Name, Title: title})
+		case bPage.SortIdx != cPage.SortIdx:
+			pages = append(pages, DiffItem{Action: diffActionChanged, Key: title, From: bPage.Name, To: cPage.Name, Title: title,
+				Changes: []ImportFieldChange{{Field: "sortIdx", Before: strconv.Itoa(bPage.SortIdx), After: strconv.Itoa(cPage.SortIdx)}}})
+		}
+
+		bWidgets, cWidgets := indexWidgets(bPage, bIdx), indexWidgets(cPage, cIdx)
+		for _, key := range unionKeys(bWidgets, cWidgets) {
+			bWidget, inBefore := bWidgets[key]
+			cWidget, inAfter := cWidgets[key]
+			item := DiffItem{Key: key, From: bWidget.Port, To: cWidget.Port, Title: cWidget.Name}
+			switch {
+			case !inBefore:
+				item.Action = diffActionAdded
+			case !inAfter:
+				item.Action = diffActionRemoved
+				item.Title = bWidget.Name
+			default:
+				item.Changes = diffWidgets(bWidget, cWidget)
+				if len(item.Changes) == 0 {
+					continue
+				}
+				item.Action = diffActionChanged
+			}
+			widgets = append(widgets, item)
+		}
+	}
+	return pages, widgets
+}
+
+// diffWidgets lists the name, grid and schema patch changes of a widget.
+func diffWidgets(before, after utils.ExportWidget) []ImportFieldChange {
+	var changes []ImportFieldChange
+	if before.Name != after.Name {
+		changes = append(changes, ImportFieldChange{Field: "name", Before: before.Name, After: after.Name})
+	}
+	grid := func(w utils.ExportWidget) string {
+		return fmt.Sprintf("%d,%d %dx%d", w.GridX, w.GridY, w.GridW, w.GridH)
+	}
+	if grid(before) != grid(after) {
+		changes = append(changes, ImportFieldChange{Field: "grid", Before: grid(before), After: grid(after)})
+	}
+	if change, ok := diffJSONField("schemaPatch", decodeRawJSON(before.SchemaPatch), decodeRawJSON(after.SchemaPatch)); ok {
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// diffScenarios compares scenarios by name and their port data by the port it is sent to.
+func diffScenarios(before, after []utils.ExportScenario, beforeKeys, afterKeys map[string]string) []DiffItem {
+	bIdx := &diffIndex{nodeKeys: beforeKeys}
+	cIdx := &diffIndex{nodeKeys: afterKeys}
+
+	indexScenarios := func(list []utils.ExportScenario) map[string]utils.ExportScenario {
+		m := make(map[string]utils.ExportScenario, len(list))
+		for _, s := range list {
+			m[s.Name] = s
+		}
+		return m
+	}
+	portData := func(s utils.ExportScenario, idx *diffIndex) map[string]interface{} {
+		m := make(map[string]interface{}, len(s.Ports))
+		for _, p := range s.Ports {
+			m[idx.portKey(p.Port)] = decodeRawJSON(p.Data)
+		}
+		return m
+	}
+
+	bScenarios, cScenarios := indexScenarios(before), indexScenarios(after)
+	var items []DiffItem
+	for _, name := range unionKeys(bScenarios, cScenarios) {
+		bScenario, inBefore := bScenarios[name]
+		cScenario, inAfter := cScenarios[name]
+		item := DiffItem{Key: name, Title: name}
+		switch {
+		case !inBefore:
+			item.Action = diffActionAdded
+		case !inAfter:
+			item.Action = diffActionRemoved
+		default:
+			bPorts, cPorts := portData(bScenario, bIdx), portData(cScenario, cIdx)
+			for _, port := range unionKeys(bPorts, cPorts) {
+				if change, ok := diffJSONField("ports["+port+"]", bPorts[port], cPorts[port]); ok {
+					item.Changes = append(item.Changes, change)
+				}
+			}
+			if len(item.Changes) == 0 {
+				continue
+			}
+			item.Action = diffActionChanged
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// summarize counts the diff items by action.
+func (d *ProjectDiff) summarize() {
+	d.Summary = DiffSummary{}
+	for _, list := range [][]DiffItem{d.Flows, d.Nodes, d.Edges, d.Pages, d.Widgets, d.Scenarios} {
+		for _, item := range list {
+			switch item.Action {
+			case diffActionAdded:
+				d.Summary.Added++
+			case diffActionRemoved:
+				d.Summary.Removed++
+			case diffActionChanged:
+				d.Summary.Changed++
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tiny-systems/module/pkg/utils"
+)
+
+func TestDiffExports(t *testing.T) {
+	elements := func(s string) []map[string]interface{} {
+		var result []map[string]interface{}
+		for _, v := range parseTestJSON(t, s).([]interface{}) {
+			result = append(result, v.(map[string]interface{}))
+		}
+		return result
+	}
+
+	// The same project in a cluster and imported elsewhere, with other resource names
+	cluster := func() *utils.ProjectExport {
+		return &utils.ProjectExport{
+			TinyFlows: []utils.ExportFlow{{ResourceName: "flow-1a2b", Name: "Main"}},
+			Elements: elements(`[
+				{"id":"main-ticker-aaaa","type":"tinyNode","flow":"flow-1a2b","position":{"x":0,"y":0},"data":{"component":"ticker","label":"Tick","handles":[{"id":"settings","configuration":{"delay":1000}}]}},
+				{"id":"main-debug-bbbb","type":"tinyNode","flow":"flow-1a2b","position":{"x":100,"y":0},"data":{"component":"debug","label":"Out"}},
+				{"id":"main-debug-cccc","type":"tinyNode","flow":"flow-1a2b","position":{"x":100,"y":50},"data":{"component":"debug","label":"Out"}},
+				{"id":"e1","type":"tinyEdge","source":"main-ticker-aaaa","sourceHandle":"out","target":"main-debug-bbbb","targetHandle":"in","data":{"configuration":{"context":"{{$}}"}}}
+			]`),
+			Pages: []utils.ExportPage{{Name: "page-x", Title: "Overview", SortIdx: 1, Widgets: []utils.ExportWidget{
+				{Name: "Delay", Port: "main-ticker-aaaa:settings", GridW: 4, GridH: 2},
+			}}},
+			Scenarios: []utils.ExportScenario{{Name: "Smoke", Ports: []utils.ExportScenarioPortData{
+				{Port: "main-debug-bbbb:in", Data: []byte(`{"a":1}`)},
+			}}},
+		}
+	}
+	imported := func() *utils.ProjectExport {
+		return &utils.ProjectExport{
+			TinyFlows: []utils.ExportFlow{{ResourceName: "flow-9z8y", Name: "Main"}},
+			Elements: elements(`[
+				{"id":"main-ticker-1111","type":"tinyNode","flow":"flow-9z8y","position":{"x":0,"y":0},"data":{"component":"ticker","label":"Tick","handles":[{"id":"settings","configuration":{"delay":1000}}]}},
+				{"id":"main-debug-3333","type":"tinyNode","flow":"flow-9z8y","position":{"x":100,"y":50},"data":{"component":"debug","label":"Out"}},
+				{"id":"main-debug-2222","type":"tinyNode","flow":"flow-9z8y","position":{"x":100,"y":0},"data":{"component":"debug","label":"Out"}},
+				{"id":"e9","type":"tinyEdge","source":"main-ticker-1111","sourceHandle":"out","target":"main-debug-2222","targetHandle":"in","data":{"configuration":{"context":"{{$}}"}}}
+			]`),
+			Pages: []utils.ExportPage{{Name: "page-y", Title: "Overview", SortIdx: 1, Widgets: []utils.ExportWidget{
+				{Name: "Delay", Port: "main-ticker-1111:settings", GridW: 4, GridH: 2},
+			}}},
+			Scenarios: []utils.ExportScenario{{Name: "Smoke", Ports: []utils.ExportScenarioPortData{
+				{Port: "main-debug-2222:in", Data: []byte(`{"a":1}`)},
+			}}},
+		}
+	}
+
+	const (
+		tickKey = "Main/ticker/Tick"
+		outKey  = "Main/debug/Out"
+		edgeKey = tickKey + ":out -> " + outKey + ":in"
+	)
+
+	tests := []struct {
+		name       string
+		before     *utils.ProjectExport
+		after      func() *utils.ProjectExport
+		singleFlow bool
+		want       *ProjectDiff
+	}{
+		{
+			name:   "import of the same project",
+			before: cluster(),
+			after:  imported,
+			want:   &ProjectDiff{},
+		},
+		{
+			name:   "flow added",
+			before: cluster(),
+			after: func() *utils.ProjectExport {
+				e := imported()
+				e.TinyFlows = append(e.TinyFlows, utils.ExportFlow{ResourceName: "flow-new", Name: "Extra"})
+				return e
+			},
+			want: &ProjectDiff{
+				Flows:   []DiffItem{{Action: diffActionAdded, Key: "Extra", To: "flow-new", Title: "Extra"}},
+				Summary: DiffSummary{Added: 1},
+			},
+		},
+		{
+			name:   "node configuration and edge changes",
+			before: cluster(),
+			after: func() *utils.ProjectExport {
+				e := imported()
+				handles := e.Elements[0]["data"].(map[string]interface{})["handles"].([]interface{})
+				handles[0].(map[string]interface{})["configuration"] = map[string]interface{}{"delay": float64(500)}
+				e.Elements[3]["target"] = "main-debug-3333"
+				return e
+			},
+			want: &ProjectDiff{
+				Nodes: []DiffItem{{Action: diffActionChanged, Key: tickKey, From: "main-ticker-aaaa", To: "main-ticker-1111", Title: "Tick", Changes: []ImportFieldChange{
+					{Field: "ports[settings].configuration", Before: `{"delay":1000}`, After: `{"delay":500}`},
+				}}},
+				Edges: []DiffItem{
+					{Action: diffActionAdded, Key: tickKey + ":out -> " + outKey + "#2:in", To: "e9"},
+					{Action: diffActionRemoved, Key: edgeKey, From: "e1"},
+				},
+				Summary: DiffSummary{Added: 1, Removed: 1, Changed: 1},
+			},
+		},
+		{
+			name:   "pages, widgets and scenarios",
+			before: cluster(),
+			after: func() *utils.ProjectExport {
+				e := imported()
+				e.Pages[0].SortIdx = 3
+				e.Pages[0].Widgets[0].GridW = 6
+				e.Pages = append(e.Pages, utils.ExportPage{Name: "page-z", Title: "Details"})
+				e.Scenarios[0].Ports[0].Data = []byte(`{"a":2}`)
+				return e
+			},
+			want: &ProjectDiff{
+				Pages: []DiffItem{
+					{Action: diffActionAdded, Key: "Details", To: "page-z", Title: "Details"},
+					{Action: diffActionChanged, Key: "Overview", From: "page-x", To: "page-y", Title: "Overview", Changes: []ImportFieldChange{
+						{Field: "sortIdx", Before: "1", After: "3"},
+					}},
+				},
+				Widgets: []DiffItem{{Action: diffActionChanged, Key: "Overview/" + tickKey + ":settings", From: "main-ticker-aaaa:settings", To: "main-ticker-1111:settings", Title: "Delay", Changes: []ImportFieldChange{
+					{Field: "grid", Before: "0,0 4x2", After: "0,0 6x2"},
+				}}},
+				Scenarios: []DiffItem{{Action: diffActionChanged, Key: "Smoke", Title: "Smoke", Changes: []ImportFieldChange{
+					{Field: "ports[" + outKey + ":in]", Before: `{"a":1}`, After: `{"a":2}`},
+				}}},
+				Summary: DiffSummary{Added: 1, Changed: 3},
+			},
+		},
+		{
+			name:   "single flow ignores flow titles",
+			before: cluster(),
+			after: func() *utils.ProjectExport {
+				e := imported()
+				e.TinyFlows[0].Name = "Copy of Main"
+				e.Pages = nil
+				return e
+			},
+			singleFlow: true,
+			want:       &ProjectDiff{},
+		},
+		{
+			name:   "node removed",
+			before: cluster(),
+			after: func() *utils.ProjectExport {
+				e := imported()
+				e.Elements = e.Elements[:1]
+				e.Scenarios = nil
+				e.Pages = nil
+				return e
+			},
+			singleFlow: true,
+			want: &ProjectDiff{
+				Nodes: []DiffItem{
+					{Action: diffActionRemoved, Key: "/debug/Out", From: "main-debug-bbbb", Title: "Out"},
+					{Action: diffActionRemoved, Key: "/debug/Out#2", From: "main-debug-cccc", Title: "Out"},
+				},
+				Edges:   []DiffItem{{Action: diffActionRemoved, Key: "/ticker/Tick:out -> /debug/Out:in", From: "e1"}},
+				Summary: DiffSummary{Removed: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffExports(tt.before, tt.after(), tt.singleFlow)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffExports() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tiny-systems/module/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	watchKindFlow    = "flow"
+	watchKindProject = "project"
+
+	// watchRetryInitial and watchRetryMax bound the backoff between re-attach attempts
+	watchRetryInitial = time.Second
+	watchRetryMax     = time.Minute
+)
+
+// newWatchBackoff returns the exponential backoff with jitter used between re-attach attempts.
+func newWatchBackoff() wait.Backoff {
+	return wait.Backoff{
+		Duration: watchRetryInitial,
+		Factor:   2,
+		Jitter:   0.5,
+		Steps:    math.MaxInt32,
+		Cap:      watchRetryMax,
+	}
+}
+
+// watchKey identifies a shared watch stream. Subscribers with the same key share one stream.
+type watchKey struct {
+	kind      string
+	context   string
+	namespace string
+	project   string
+	flow      string
+}
+
+// WatchSubscription is handed to the frontend; node updates are emitted on Event.
+type WatchSubscription struct {
+	ID    string `json:"id"`
+	Event string `json:"event"`
+}
+
+// WatchSubscriptionStatus reports the health of a single subscription.
+type WatchSubscriptionStatus struct {
+	ID          string `json:"id"`
+	Event       string `json:"event"`
+	Kind        string `json:"kind"`
+	Context     string `json:"context"`
+	Namespace   string `json:"namespace"`
+	Project     string `json:"project"`
+	Flow        string `json:"flow,omitempty"`
+	Subscribers int    `json:"subscribers"` // subscriptions sharing the same stream
+	Healthy     bool   `json:"healthy"`
+	State       string `json:"state"` // connected, reconnecting or failed
+	Attempt     int    `json:"attempt"`
+	Error       string `json:"error,omitempty"`
+	CreatedAt   int64  `json:"createdAt"`   // unix millis
+	LastEventAt int64  `json:"lastEventAt"` // unix millis of the last emitted update
+}
+
+// watchHandler turns a node event into the payloads emitted to subscribers.
+// nodes returns all project nodes with the event already applied.
+type watchHandler func(ctx context.Context, eventType watch.EventType, node *v1alpha1.TinyNode, nodes func() map[string]v1alpha1.TinyNode) []interface{}
+
+// watchExtra runs alongside a stream for its whole life (heartbeats, stats streaming).
+type watchExtra func(ctx context.Context, emit func(interface{}))
+
+type watchSubscriber struct {
+	id        string
+	event     string
+	createdAt time.Time
+}
+
+// watchStream delivers node events of one key to all of its subscribers.
+type watchStream struct {
+	key     watchKey
+	hub     *watchHub
+	handler watchHandler
+	cancel  context.CancelFunc
+
+	mu          sync.Mutex
+	subscribers map[string]*watchSubscriber
+	nc          *projectNodeCache
+	attached    bool // attached at least once, later attaches resync subscribers
+	state       string
+	attempt     int
+	err         error
+	lastEventAt time.Time
+}
+
+// watchHub reference-counts node watch subscriptions on top of the shared node caches,
+// so several flows and dashboards can be live at the same time.
+type watchHub struct {
+	pool *clientPool
+
+	// emit sends a payload to the frontend on the given event name
+	emit func(event string, data interface{})
+	// onStatus receives connection state changes of streams
+	onStatus func(WatchStatus)
+
+	mu            sync.Mutex
+	streams       map[watchKey]*watchStream
+	subscriptions map[string]*watchStream
+}
+
+func newWatchHub(pool *clientPool) *watchHub {
+	return &watchHub{
+		pool:          pool,
+		streams:       make(map[watchKey]*watchStream),
+		subscriptions: make(map[string]*watchStream),
+	}
+}
+
+// subscribe adds a subscriber to the stream of key, starting the stream if it isn't running.
+func (h *watchHub) subscribe(key watchKey, baseEvent string, handler watchHandler, extra watchExtra) WatchSubscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.streams[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		s = &watchStream{
+			key:         key,
+			hub:         h,
+			handler:     handler,
+			cancel:      cancel,
+			subscribers: make(map[string]*watchSubscriber),
+			state:       watchStateReconnecting,
+		}
+		h.streams[key] = s
+		go s.run(ctx)
+		if extra != nil {
+			go extra(ctx, s.broadcast)
+		}
+	}
+
+	id := uuid.New().String()
+	sub := &watchSubscriber{
+		id:        id,
+		event:     fmt.Sprintf("%s:%s", baseEvent, id),
+		createdAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.subscribers[id] = sub
+	s.mu.Unlock()
+
+	h.subscriptions[id] = s
+	return WatchSubscription{ID: sub.id, Event: sub.event}
+}
+
+// unsubscribe removes a subscriber and stops its stream once nobody is left.
+func (h *watchHub) unsubscribe(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.subscriptions[id]
+	if !ok {
+		return
+	}
+	delete(h.subscriptions, id)
+
+	s.mu.Lock()
+	delete(s.subscribers, id)
+	left := len(s.subscribers)
+	s.mu.Unlock()
+
+	if left == 0 {
+		s.cancel()
+		delete(h.streams, s.key)
+	}
+}
+
+// statuses returns the health of all active subscriptions.
+func (h *watchHub) statuses() []WatchSubscriptionStatus {
+	h.mu.Lock()
+	streams := make([]*watchStream, 0, len(h.streams))
+	for _, s := range h.streams {
+		streams = append(streams, s)
+	}
+	h.mu.Unlock()
+
+	result := make([]WatchSubscriptionStatus, 0)
+	for _, s := range streams {
+		result = append(result, s.statuses()...)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt < result[j].CreatedAt
+	})
+	return result
+}
+
+// close stops all streams.
+func (h *watchHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, s := range h.streams {
+		s.cancel()
+	}
+	h.streams = make(map[watchKey]*watchStream)
+	h.subscriptions = make(map[string]*watchStream)
+}
+
+// run attaches the stream to the project's node cache and re-attaches whenever
+// the cache is dropped (auth refresh, context invalidation) until ctx is cancelled.
+// Failed attempts are retried with exponential backoff and jitter, and never give up.
+func (s *watchStream) run(ctx context.Context) {
+	backoff := newWatchBackoff()
+	for {
+		err := s.attach(ctx, &backoff)
+		if ctx.Err() != nil {
+			return
+		}
+		s.setFailure(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// attach streams events from the current node cache until it stops or ctx is cancelled.
+// The backoff is reset once the stream is attached. The cache's initial list isn't replayed,
+// subscribers load the nodes themselves; after a re-attach they get one snapshot instead,
+// as events may have been missed while the stream was detached.
+func (s *watchStream) attach(ctx context.Context, backoff *wait.Backoff) error {
+	syncCtx, cancel := context.WithTimeout(ctx, nodeCacheSyncTimeout)
+	nc, err := s.hub.pool.nodeCache(syncCtx, s.key.context, s.key.namespace, s.key.project)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	nc.pin()
+	defer nc.unpin()
+
+	reg, err := nc.informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if isInInitialList {
+				return
+			}
+			s.handle(ctx, watch.Added, obj, nc)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			s.handle(ctx, watch.Modified, obj, nc)
+		},
+		DeleteFunc: func(obj interface{}) {
+			s.handle(ctx, watch.Deleted, obj, nc)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to add event handler: %w", err)
+	}
+	defer func() {
+		_ = nc.informer.RemoveEventHandler(reg)
+	}()
+
+	*backoff = newWatchBackoff()
+	if s.setAttached(nc) {
+		s.resync(ctx, nc)
+	}
+	defer s.setAttached(nil)
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-nc.done():
+		return errors.New("node cache stopped")
+	}
+}
+
+// handle converts an informer notification and fans the payloads out to all subscribers.
+func (s *watchStream) handle(ctx context.Context, eventType watch.EventType, obj interface{}, nc *projectNodeCache) {
+	if ctx.Err() != nil {
+		return
+	}
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	node, ok := obj.(*v1alpha1.TinyNode)
+	if !ok {
+		return
+	}
+
+	// the handler may ask for the project nodes several times, copy them once
+	var snapshot map[string]v1alpha1.TinyNode
+	nodes := func() map[string]v1alpha1.TinyNode {
+		if snapshot == nil {
+			snapshot = nc.nodesMap()
+		}
+		return snapshot
+	}
+	s.emit(ctx, eventType, node, nodes)
+}
+
+// resync emits every node of the cache from a single snapshot.
+func (s *watchStream) resync(ctx context.Context, nc *projectNodeCache) {
+	snapshot := nc.nodesMap()
+	nodes := func() map[string]v1alpha1.TinyNode {
+		return snapshot
+	}
+	for name := range snapshot {
+		if ctx.Err() != nil {
+			return
+		}
+		node := snapshot[name]
+		s.emit(ctx, watch.Added, &node, nodes)
+	}
+}
+
+// emit runs the handler for a node and fans the payloads out to all subscribers.
+func (s *watchStream) emit(ctx context.Context, eventType watch.EventType, node *v1alpha1.TinyNode, nodes func() map[string]v1alpha1.TinyNode) {
+	for _, payload := range s.handler(ctx, eventType, node, nodes) {
+		s.broadcast(payload)
+	}
+
+	s.mu.Lock()
+	s.lastEventAt = time.Now()
+	s.mu.Unlock()
+}
+
+// broadcast emits a payload on the event name of every subscriber.
+func (s *watchStream) broadcast(payload interface{}) {
+	if s.hub.emit == nil {
+		return
+	}
+
+	s.mu.Lock()
+	events := make([]string, 0, len(s.subscribers))
+	for _, sub := range s.subscribers {
+		events = append(events, sub.event)
+	}
+	s.mu.Unlock()
+
+	for _, event := range events {
+		s.hub.emit(event, payload)
+	}
+}
+
+// setAttached records the node cache the stream currently reads from, and reports
+// whether the stream was attached before.
+func (s *watchStream) setAttached(nc *projectNodeCache) bool {
+	s.mu.Lock()
+	s.nc = nc
+	reattached := s.attached
+	if nc != nil {
+		s.state = watchStateConnected
+		s.attempt = 0
+		s.err = nil
+		s.attached = true
+	}
+	s.mu.Unlock()
+
+	if nc != nil {
+		s.reportStatus()
+	}
+	return reattached
+}
+
+// setFailure records a failed attach attempt.
+func (s *watchStream) setFailure(err error) {
+	s.mu.Lock()
+	s.attempt++
+	s.err = err
+	s.state = watchStateReconnecting
+	if s.attempt >= watchFailedAttempts {
+		s.state = watchStateFailed
+	}
+	s.mu.Unlock()
+
+	s.reportStatus()
+}
+
+// watchStatus returns the connection state of the stream; while attached it is
+// the state of the node cache's watch.
+func (s *watchStream) watchStatus() WatchStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.nc != nil {
+		st := s.nc.watchStatus()
+		st.Flow = s.key.flow
+		return st
+	}
+
+	st := WatchStatus{
+		Context:   s.key.context,
+		Namespace: s.key.namespace,
+		Project:   s.key.project,
+		Flow:      s.key.flow,
+		State:     s.state,
+		Attempt:   s.attempt,
+	}
+	if s.err != nil {
+		st.Error = s.err.Error()
+	}
+	return st
+}
+
+func (s *watchStream) reportStatus() {
+	if s.hub.onStatus != nil {
+		s.hub.onStatus(s.watchStatus())
+	}
+}
+
+func (s *watchStream) statuses() []WatchSubscriptionStatus {
+	ws := s.watchStatus()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]WatchSubscriptionStatus, 0, len(s.subscribers))
+	for _, sub := range s.subscribers {
+		st := WatchSubscriptionStatus{
+			ID:          sub.id,
+			Event:       sub.event,
+			Kind:        s.key.kind,
+			Context:     s.key.context,
+			Namespace:   s.key.namespace,
+			Project:     s.key.project,
+			Flow:        s.key.flow,
+			Subscribers: len(s.subscribers),
+			Healthy:     ws.State == watchStateConnected,
+			State:       ws.State,
+			Attempt:     ws.Attempt,
+			Error:       ws.Error,
+			CreatedAt:   sub.createdAt.UnixMilli(),
+		}
+		if !s.lastEventAt.IsZero() {
+			st.LastEventAt = s.lastEventAt.UnixMilli()
+		}
+		result = append(result, st)
+	}
+	return result
+}
+
+// GetWatchSubscriptions returns the health of all live node watch subscriptions.
+func (a *App) GetWatchSubscriptions() []WatchSubscriptionStatus {
+	return a.watches.statuses()
+}